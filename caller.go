@@ -0,0 +1,233 @@
+package zlogger
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Format of caller info at the head of log.
+const (
+	CallerShort   = 0 // file.go:12
+	CallerPackage = 1 // pkg/file.go:12
+	CallerModule  = 2 // path/in/module/file.go:12
+	CallerFull    = 3 // /full/build/path/file.go:12
+	CallerOff     = 4 // No caller info, skip runtime.Callers
+)
+
+// callerFrame is a resolved frame of caller, cached by PC.
+type callerFrame struct {
+	callers  [CallerOff]string // File & line of every caller format
+	function string            // Function name without package path
+}
+
+var (
+	// callerCache cache *callerFrame by PC, runtime.CallersFrames is not cheap.
+	callerCache      = make(map[uintptr]*callerFrame)
+	callerCacheMutex sync.RWMutex
+	// modulePaths contain paths of modules in build, longest first.
+	modulePaths     []string
+	modulePathsOnce sync.Once
+	// moduleRoots contain build dirs of modules, learned from files of packages.
+	moduleRoots      []string
+	moduleRootsMutex sync.Mutex
+)
+
+// SetCallerFormat set the format of caller info.
+// Child loggers follow the format of root.
+func (logger *Logger) SetCallerFormat(format uint8) {
+	logger.resolve().root().callerFormat.Store(format)
+}
+
+func (logger *Logger) GetCallerFormat() uint8 {
+	return logger.resolve().root().callerFormat.Load().(uint8)
+}
+
+// SetCallerFunction set whether function name is added to caller info.
+// Like file.go:12:pkg.(*T).Method: in text.
+func (logger *Logger) SetCallerFunction(enable bool) {
+	logger.resolve().root().callerFunc.Store(enable)
+}
+
+// getCaller get file name & line of call function, and function name if enabled.
+// depth is the same as runtime.Caller.
+func (logger *Logger) getCaller(depth int) (string, string) {
+	root := logger.root()
+	format := root.callerFormat.Load().(uint8)
+	if format >= CallerOff {
+		return "", ""
+	}
+	var pcs [1]uintptr
+	// Skip runtime.Callers itself, same as runtime.Caller(depth).
+	if runtime.Callers(depth+1, pcs[:]) == 0 {
+		return "???:0", ""
+	}
+	frame := getCallerFrame(pcs[0])
+	if root.callerFunc.Load() {
+		return frame.callers[format], frame.function
+	}
+	return frame.callers[format], ""
+}
+
+// getCallerFrame resolve frame of pc, or get it from cache.
+func getCallerFrame(pc uintptr) *callerFrame {
+	callerCacheMutex.RLock()
+	f, ok := callerCache[pc]
+	callerCacheMutex.RUnlock()
+	if ok {
+		return f
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file := frame.File
+	if file == "" {
+		file = "???"
+	}
+	f = &callerFrame{}
+	pkgPath, function := splitFunctionName(frame.Function)
+	f.function = function
+	f.callers[CallerFull] = file
+	// I don't like log path. Use short.
+	f.callers[CallerShort] = file[strings.LastIndexByte(file, '/')+1:]
+	f.callers[CallerPackage] = f.callers[CallerShort]
+	if i := strings.LastIndexByte(file, '/'); i > 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			f.callers[CallerPackage] = file[j+1:]
+		}
+	}
+	f.callers[CallerModule] = getModuleRelativePath(pkgPath, file)
+	if f.callers[CallerModule] == "" {
+		f.callers[CallerModule] = f.callers[CallerPackage]
+	}
+	line := ":" + strconv.Itoa(frame.Line)
+	for i := range f.callers {
+		f.callers[i] += line
+	}
+	callerCacheMutex.Lock()
+	callerCache[pc] = f
+	callerCacheMutex.Unlock()
+	return f
+}
+
+// splitFunctionName split full function name to package path & short name.
+// github.com/a/b/pkg.(*T).Method => github.com/a/b/pkg, pkg.(*T).Method
+func splitFunctionName(name string) (string, string) {
+	if name == "" {
+		return "", "???"
+	}
+	slash := strings.LastIndexByte(name, '/')
+	dot := strings.IndexByte(name[slash+1:], '.')
+	if dot < 0 {
+		return "", name[slash+1:]
+	}
+	return name[:slash+1+dot], name[slash+1:]
+}
+
+// getModuleRelativePath get path of file relative to its module root.
+// The build prefix of file is trimmed, fallback to import path of package.
+// Return empty if module root of file in package main is unknown.
+func getModuleRelativePath(pkgPath, file string) string {
+	short := file[strings.LastIndexByte(file, '/')+1:]
+	if pkgPath == "" {
+		return short
+	}
+	modulePathsOnce.Do(loadModulePaths)
+	if pkgPath == "main" {
+		return getMainRelativePath(file)
+	}
+	for _, mod := range modulePaths {
+		if pkgPath == mod || strings.HasPrefix(pkgPath, mod+"/") {
+			rel := strings.TrimPrefix(pkgPath[len(mod):], "/")
+			addModuleRoot(file, rel)
+			if rel == "" {
+				return short
+			}
+			return rel + "/" + short
+		}
+	}
+	return pkgPath + "/" + short
+}
+
+// getMainRelativePath get path of file in package main relative to its module root.
+// Import path of package main is not in the binary, so module root is
+// the module path if built with -trimpath, or a build dir learned from
+// other packages, or the dir of go.mod above file.
+func getMainRelativePath(file string) string {
+	for _, mod := range modulePaths {
+		if strings.HasPrefix(file, mod+"/") {
+			return file[len(mod)+1:]
+		}
+	}
+	root := ""
+	moduleRootsMutex.Lock()
+	for _, dir := range moduleRoots {
+		if strings.HasPrefix(file, dir+"/") && len(dir) > len(root) {
+			root = dir
+		}
+	}
+	moduleRootsMutex.Unlock()
+	if root == "" {
+		// go.mod is there if binary runs where it is built.
+		for dir := path.Dir(file); ; dir = path.Dir(dir) {
+			if _, err := os.Stat(path.Join(dir, "go.mod")); err == nil {
+				root = dir
+				break
+			}
+			if dir == path.Dir(dir) {
+				return ""
+			}
+		}
+	}
+	return strings.TrimPrefix(file[len(root):], "/")
+}
+
+// addModuleRoot learn build dir of module from file of package at rel in module.
+func addModuleRoot(file, rel string) {
+	dir := path.Dir(file)
+	if rel != "" {
+		if !strings.HasSuffix(dir, "/"+rel) {
+			return
+		}
+		dir = dir[:len(dir)-len(rel)-1]
+	}
+	moduleRootsMutex.Lock()
+	defer moduleRootsMutex.Unlock()
+	for _, root := range moduleRoots {
+		if root == dir {
+			return
+		}
+	}
+	moduleRoots = append(moduleRoots, dir)
+}
+
+func loadModulePaths() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if info.Main.Path != "" {
+		modulePaths = append(modulePaths, info.Main.Path)
+	}
+	for _, dep := range info.Deps {
+		modulePaths = append(modulePaths, dep.Path)
+	}
+	sort.Slice(modulePaths, func(i, j int) bool {
+		return len(modulePaths[i]) > len(modulePaths[j])
+	})
+}
+
+func SetCallerFormat(format uint8) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetCallerFormat(format)
+}
+
+func SetCallerFunction(enable bool) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetCallerFunction(enable)
+}
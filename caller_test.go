@@ -0,0 +1,97 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCallerFormat(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_caller", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	wd, _ := os.Getwd()
+	cases := []struct {
+		format   uint8
+		function bool
+		expect   string
+	}{
+		{CallerShort, false, " caller_test.go:"},
+		{CallerPackage, false, " " + wd[strings.LastIndexByte(wd, '/')+1:] + "/caller_test.go:"},
+		{CallerModule, false, " caller_test.go:"},
+		{CallerFull, false, " " + wd + "/caller_test.go:"},
+		{CallerShort, true, ":zlogger.TestCallerFormat: [INFO]"},
+		{CallerOff, false, ""},
+	}
+	for _, c := range cases {
+		l.SetCallerFormat(c.format)
+		l.SetCallerFunction(c.function)
+		l.Named("child").Info("caller format")
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, c := range cases {
+		line := readLine(reader)
+		if c.format == CallerOff {
+			if strings.Contains(line, ".go:") {
+				t.Error("Caller off log line is", line)
+			}
+			continue
+		}
+		if !strings.Contains(line, c.expect) {
+			t.Error("Caller format", c.format, "log line is", line)
+		}
+	}
+}
+
+func TestSplitFunctionName(t *testing.T) {
+	pkg, fn := splitFunctionName("github.com/a/b.c/pkg.(*T).Method")
+	if pkg != "github.com/a/b.c/pkg" || fn != "pkg.(*T).Method" {
+		t.Error("Split function name is", pkg, fn)
+	}
+	pkg, fn = splitFunctionName("main.main")
+	if pkg != "main" || fn != "main.main" {
+		t.Error("Split function name is", pkg, fn)
+	}
+}
+
+func TestModuleRelativePath(t *testing.T) {
+	pc, _, _, _ := runtime.Caller(0)
+	mod, _ := splitFunctionName(runtime.FuncForPC(pc).Name())
+	tmp := t.TempDir()
+	if err := os.WriteFile(tmp+"/go.mod", []byte("module app\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		pkgPath string
+		file    string
+		expect  string
+	}{
+		{mod, "/build/zlogger/zlogger.go", "zlogger.go"},
+		{mod + "/reader", "/build/zlogger/reader/reader.go", "reader/reader.go"},
+		// Build dir of module is learned from files above.
+		{"main", "/build/zlogger/cmd/zlogger/main.go", "cmd/zlogger/main.go"},
+		// Built with -trimpath.
+		{"main", mod + "/cmd/app/main.go", "cmd/app/main.go"},
+		{"main", tmp + "/cmd/app/main.go", "cmd/app/main.go"},
+		{"main", "/nowhere/app/main.go", ""},
+		{"", "/nowhere/app/main.go", "main.go"},
+	}
+	for _, c := range cases {
+		if rel := getModuleRelativePath(c.pkgPath, c.file); rel != c.expect {
+			t.Error("Module relative path of", c.file, "is", rel, "not", c.expect)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zhangyu0310/zlogger"
+	"github.com/zhangyu0310/zlogger/reader"
+)
+
+// runGrep print entries of logs matched by filter & --match.
+func runGrep(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("grep", flag.ContinueOnError)
+	var ff filterFlags
+	ff.register(fs)
+	match := fs.String("match", "", "entries which message contains it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return ErrNoLog
+	}
+	filter, err := ff.filter(time.Now())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	defer func() { _ = w.Flush() }()
+	for _, log := range fs.Args() {
+		r, err := openLog(log, filter)
+		if err != nil {
+			return err
+		}
+		err = forEach(r, func(e *reader.Entry) error {
+			if !strings.Contains(e.Message, *match) {
+				return nil
+			}
+			return writeRaw(w, e)
+		})
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEach call fn with every entry of r.
+func forEach(r *reader.FileReader, fn func(e *reader.Entry) error) error {
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+// runMerge print entries of logs interleaved by time.
+func runMerge(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	var ff filterFlags
+	ff.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return ErrNoLog
+	}
+	filter, err := ff.filter(time.Now())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	defer func() { _ = w.Flush() }()
+	return merge(fs.Args(), filter, func(e *reader.Entry) error {
+		return writeRaw(w, e)
+	})
+}
+
+// merge call fn with entries of logs in time order.
+// Entries of a log keep their order, even if time goes back.
+func merge(logs []string, filter reader.Filter, fn func(e *reader.Entry) error) error {
+	readers := make([]*reader.FileReader, 0, len(logs))
+	defer func() {
+		for _, r := range readers {
+			_ = r.Close()
+		}
+	}()
+	heads := make([]*reader.Entry, 0, len(logs))
+	for _, log := range logs {
+		r, err := openLog(log, filter)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, r)
+		heads = append(heads, nil)
+	}
+	// Get the first entry of every log.
+	for i, r := range readers {
+		e, err := r.Next()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		heads[i] = e
+	}
+	for {
+		next := -1
+		for i, e := range heads {
+			if e != nil && (next < 0 || e.Time.Before(heads[next].Time)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return nil
+		}
+		if err := fn(heads[next]); err != nil {
+			return err
+		}
+		e, err := readers[next].Next()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		heads[next] = e
+	}
+}
+
+// runConvert print entries of logs in another format, one entry per line.
+func runConvert(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	var ff filterFlags
+	ff.register(fs)
+	to := fs.String("to", "json", "format of output: json, logfmt or text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return ErrNoLog
+	}
+	var enc zlogger.Encoder
+	switch *to {
+	case "json":
+		enc = zlogger.JSONEncoder{}
+	case "logfmt":
+		enc = zlogger.LogfmtEncoder{}
+	case "text":
+		enc = zlogger.TextEncoder{Multiline: zlogger.MultilineIndent}
+	default:
+		return ErrInvalidTo
+	}
+	filter, err := ff.filter(time.Now())
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(stdout)
+	defer func() { _ = w.Flush() }()
+	var buf []byte
+	return merge(fs.Args(), filter, func(e *reader.Entry) error {
+		buf = enc.Encode(buf[:0], toEntry(e))
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+// toEntry convert parsed entry to entry of zlogger to encode it.
+// Fields of JSON log are kept in order of keys.
+func toEntry(e *reader.Entry) *zlogger.Entry {
+	entry := &zlogger.Entry{
+		Level:     e.Level,
+		Time:      e.Time,
+		Caller:    e.Caller,
+		Function:  e.Function,
+		Component: e.Component,
+		Message:   e.Message,
+		Stack:     e.Stack,
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		entry.Fields = append(entry.Fields, zlogger.Any(k, e.Fields[k]))
+	}
+	return entry
+}
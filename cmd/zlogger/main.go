@@ -0,0 +1,139 @@
+// Command zlogger tail, grep, merge & convert log files written by zlogger.
+//
+//	zlogger tail [-n 10] [-f] [--checkpoint pos.json] logs/app
+//	zlogger grep [--level error] [--since 1h] [--caller foo.go] [--match text] logs/app
+//	zlogger merge logs/api logs/worker
+//	zlogger convert --to json logs/app
+//
+// A log is a file, or path/name of a logger, which is all rotated files
+// like path/name.2006-01-02_15 in chronological order.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zhangyu0310/zlogger"
+	"github.com/zhangyu0310/zlogger/reader"
+)
+
+const usage = `Usage: zlogger <command> [flags] <log>...
+
+Commands:
+  tail     print the last entries of log, -f to follow rotations
+  grep     print entries matched by filter
+  merge    interleave entries of logs by time
+  convert  convert entries to json, logfmt or text
+
+Run 'zlogger <command> -h' for flags of command.
+`
+
+var (
+	ErrNoLog        = errors.New("no log is given")
+	ErrInvalidTime  = errors.New("invalid time, use duration like 1h or 2006-01-02T15:04:05")
+	ErrInvalidCmd   = errors.New("invalid command")
+	ErrInvalidTo    = errors.New("invalid format, use json, logfmt or text")
+	errHelpRequired = errors.New("help required")
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if err != errHelpRequired && err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "zlogger:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run run command of args, entries are written to stdout.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errHelpRequired
+	}
+	switch args[0] {
+	case "tail":
+		return runTail(args[1:], stdout)
+	case "grep":
+		return runGrep(args[1:], stdout)
+	case "merge":
+		return runMerge(args[1:], stdout)
+	case "convert":
+		return runConvert(args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stderr, usage)
+		return errHelpRequired
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidCmd, args[0])
+	}
+}
+
+// filterFlags is flags of entry filter shared by commands.
+type filterFlags struct {
+	level  string
+	since  string
+	until  string
+	caller string
+}
+
+func (f *filterFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.level, "level", "all", "entries at or above level, like info or error")
+	fs.StringVar(&f.since, "since", "", "entries after time, duration like 1h means 1h ago")
+	fs.StringVar(&f.until, "until", "", "entries before time, same format as since")
+	fs.StringVar(&f.caller, "caller", "", "entries which caller contains it, like foo.go")
+}
+
+func (f *filterFlags) filter(now time.Time) (reader.Filter, error) {
+	var filter reader.Filter
+	var err error
+	if filter.MinLevel, err = zlogger.Str2LogLevel(f.level); err != nil {
+		return filter, fmt.Errorf("%w: %s", err, f.level)
+	}
+	if filter.Since, err = parseTime(f.since, now); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseTime(f.until, now); err != nil {
+		return filter, err
+	}
+	filter.Caller = f.caller
+	return filter, nil
+}
+
+// parseTime parse s as a duration before now, or a time in local zone.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidTime, s)
+}
+
+// openLog open log, a file or all rotated files of path/name.
+func openLog(log string, filter reader.Filter) (*reader.FileReader, error) {
+	if info, err := os.Stat(log); err == nil && info.Mode().IsRegular() {
+		return reader.OpenFiles(filter, log), nil
+	}
+	path, name := filepath.Split(log)
+	if path == "" {
+		path = "."
+	}
+	return reader.Open(path, name, filter)
+}
+
+// writeRaw write lines of entry as read.
+func writeRaw(w io.Writer, e *reader.Entry) error {
+	_, err := io.WriteString(w, e.Raw+"\n")
+	return err
+}
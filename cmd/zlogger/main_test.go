@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zhangyu0310/zlogger/reader"
+)
+
+func writeLog(t *testing.T, path string, lines ...string) {
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrep(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, filepath.Join(dir, "app.2024-01-01_09"),
+		"2024/01/01 09:00:00.000000 foo.go:1: [ERROR] old error")
+	writeLog(t, filepath.Join(dir, "app.2024-01-01_10"),
+		"2024/01/01 10:00:00.000000 foo.go:1: [INFO] started",
+		"2024/01/01 10:01:00.000000 foo.go:2: [ERROR] failed",
+		"\tmain.main main.go:3",
+		"2024/01/01 10:02:00.000000 bar.go:3: [ERROR] failed too")
+	var out bytes.Buffer
+	err := run([]string{"grep", "--level", "error", "--since", "2024-01-01T09:30:00",
+		"--caller", "foo.go", "--match", "fail", filepath.Join(dir, "app")}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "2024/01/01 10:01:00.000000 foo.go:2: [ERROR] failed\n\tmain.main main.go:3\n" {
+		t.Error("Output is", out.String())
+	}
+	for _, args := range [][]string{
+		{"grep", "--level", "loud", dir + "/app"},
+		{"grep", "--since", "yesterday", dir + "/app"},
+		{"grep"},
+		{"nope"},
+	} {
+		if err = run(args, &out); err == nil {
+			t.Error("Args should fail", args)
+		}
+	}
+}
+
+func TestMergeConvert(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api.2024-01-01_10")
+	writeLog(t, api,
+		"2024/01/01 10:00:00.000000 api.go:1: [INFO] [http] a1",
+		"2024/01/01 10:00:02.000000 api.go:2: [WARN] a2")
+	// Time of text log is in local zone.
+	ts := time.Date(2024, 1, 1, 10, 0, 1, 0, time.Local).Format("2006-01-02T15:04:05.000000Z07:00")
+	writeLog(t, filepath.Join(dir, "worker.2024-01-01_10"),
+		`{"time":"`+ts+`","level":"error","msg":"w1","n":1}`)
+	var out bytes.Buffer
+	// Log file is given by path.
+	if err := run([]string{"merge", api, filepath.Join(dir, "worker")}, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "a1") ||
+		!strings.Contains(lines[1], `"w1"`) || !strings.HasSuffix(lines[2], "a2") {
+		t.Error("Merged lines are", lines)
+	}
+
+	out.Reset()
+	if err := run([]string{"convert", "--to", "json", filepath.Join(dir, "api")}, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	var m map[string]interface{}
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[0]), &m) != nil ||
+		m["msg"] != "a1" || m["logger"] != "http" || m["level"] != "info" || m["caller"] != "api.go:1" {
+		t.Error("Converted lines are", lines)
+	}
+	out.Reset()
+	if err := run([]string{"convert", "--to", "logfmt", filepath.Join(dir, "worker")}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "level=error msg=w1 n=1\n") {
+		t.Error("Converted line is", out.String())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestTail(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "app.2024-01-01_10")
+	writeLog(t, first,
+		"2024/01/01 10:00:00.000000 a.go:1: [INFO] one",
+		"2024/01/01 10:00:01.000000 a.go:1: [INFO] two",
+		"\tframe",
+		"2024/01/01 10:00:02.000000 a.go:1: [INFO] three")
+	out := &syncBuffer{}
+	path, name, file, err := splitLog(filepath.Join(dir, "app"))
+	if err != nil || path != dir+"/" || name != "app" || file != "app.2024-01-01_10" {
+		t.Fatal("Split log is", path, name, file, err)
+	}
+	pos, err := last(first, 2, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "2024/01/01 10:00:01.000000 a.go:1: [INFO] two\n\tframe\n"+
+		"2024/01/01 10:00:02.000000 a.go:1: [INFO] three\n" {
+		t.Fatal("Last entries are", out.String())
+	}
+	if _, name, _, _ = splitLog(first); name != "app" {
+		t.Error("Name of rotated file is", name)
+	}
+
+	f := reader.Follow(path, name)
+	f.Interval = 5 * time.Millisecond
+	_ = f.Seek(pos)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- followTo(f, out, stop) }()
+	appendLine := func(path, line string) {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = file.WriteString(line + "\n")
+		_ = file.Close()
+	}
+	appendLine(first, "2024/01/01 10:59:59.000000 a.go:1: [INFO] four")
+	time.Sleep(20 * time.Millisecond)
+	appendLine(filepath.Join(dir, "app.2024-01-01_11"), "2024/01/01 11:00:00.000000 a.go:1: [INFO] five")
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.HasSuffix(out.String(), "five\n") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(stop)
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "three\n2024/01/01 10:59:59.000000 a.go:1: [INFO] four\n"+
+		"2024/01/01 11:00:00.000000 a.go:1: [INFO] five\n") {
+		t.Error("Followed output is", out.String())
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zhangyu0310/zlogger/reader"
+)
+
+var (
+	ErrOneLog       = errors.New("tail needs exactly one log")
+	ErrNotFollowing = errors.New("only log of a logger or its rotated file can be followed")
+)
+
+// runTail print the last entries of log, and follow it if -f.
+func runTail(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	n := fs.Int("n", 10, "number of last entries to print")
+	follow := fs.Bool("f", false, "follow new entries, across rotations of log file")
+	interval := fs.Duration("interval", time.Second, "interval of checking new entries")
+	checkpoint := fs.String("checkpoint", "", "file to save position, resume from it if exists")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return ErrOneLog
+	}
+	path, name, file, err := splitLog(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var pos reader.Position
+	if file != "" {
+		if pos, err = last(filepath.Join(path, file), *n, stdout); err != nil {
+			return err
+		}
+	}
+	if !*follow {
+		return nil
+	}
+	if name == "" {
+		return ErrNotFollowing
+	}
+	f := reader.Follow(path, name)
+	f.Interval = *interval
+	f.Checkpoint = *checkpoint
+	if err = f.Seek(pos); err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return followTo(f, stdout, nil)
+}
+
+// splitLog get path & name of logger of log, and the newest file of it.
+// name is empty if log is a file not rotated by logger.
+func splitLog(log string) (path, name, file string, err error) {
+	path, base := filepath.Split(log)
+	if path == "" {
+		path = "."
+	}
+	if info, err := os.Stat(log); err == nil && info.Mode().IsRegular() {
+		if i := strings.LastIndexByte(base, '.'); i > 0 {
+			if _, ok := reader.FileTime(base, base[:i]); ok {
+				name = base[:i]
+			}
+		}
+		return path, name, base, nil
+	}
+	files, err := reader.Files(path, base)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(files) > 0 {
+		file = filepath.Base(files[len(files)-1])
+	}
+	return path, base, file, nil
+}
+
+// last print the last n entries of file, return position after them.
+func last(file string, n int, w io.Writer) (reader.Position, error) {
+	var next func() (*reader.Entry, error)
+	var size int64
+	if strings.HasSuffix(file, ".gz") {
+		r := reader.OpenFiles(reader.Filter{}, file)
+		defer func() { _ = r.Close() }()
+		next = r.Next
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return reader.Position{}, err
+		}
+		defer func() { _ = f.Close() }()
+		info, err := f.Stat()
+		if err != nil {
+			return reader.Position{}, err
+		}
+		// Entries written after stat are left to follower.
+		size = info.Size()
+		next = reader.NewReader(io.LimitReader(f, size)).Next
+	}
+	ring := make([]string, 0, n)
+	for {
+		e, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return reader.Position{}, err
+		}
+		if n == 0 {
+			continue
+		}
+		if len(ring) == n {
+			ring = append(ring[:0], ring[1:]...)
+		}
+		ring = append(ring, e.Raw)
+	}
+	bw := bufio.NewWriter(w)
+	for _, raw := range ring {
+		_, _ = bw.WriteString(raw + "\n")
+	}
+	return reader.Position{File: filepath.Base(file), Offset: size}, bw.Flush()
+}
+
+// followTo write entries of follower to w, until stop is closed.
+func followTo(f *reader.Follower, w io.Writer, stop <-chan struct{}) error {
+	if stop != nil {
+		go func() {
+			<-stop
+			_ = f.Close()
+		}()
+	}
+	for {
+		e, err := f.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, e.Raw+"\n"); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package zlogger
+
+import (
+	"io"
+	"os"
+)
+
+// ANSI color of level tags.
+const (
+	colorReset   = "\x1b[0m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorGray    = "\x1b[90m"
+	colorBoldRed = "\x1b[1;31m"
+)
+
+// defaultCallerWidth is the width of caller column of console encoder.
+const defaultCallerWidth = 20
+
+var levelColors = [...]string{
+	LogLevelDebug: colorBlue,
+	LogLevelInfo:  colorGreen,
+	LogLevelWarn:  colorYellow,
+	LogLevelError: colorRed,
+	LogLevelFatal: colorBoldRed,
+	LogLevelPanic: colorMagenta,
+}
+
+// ConsoleEncoder is a human friendly encoder for terminal, like:
+// 15:04:05.000000 [INFO]  file.go:12           [component] message key=value
+// Level tag is colored if Color is true, caller column is aligned.
+type ConsoleEncoder struct {
+	Color       bool // Color level tags with ANSI escape codes
+	CallerWidth int  // Width of caller column, 0 is defaultCallerWidth
+}
+
+func (enc ConsoleEncoder) Encode(buf []byte, e *Entry) []byte {
+	buf = e.Time.AppendFormat(buf, "15:04:05.000000")
+	buf = append(buf, ' ')
+	tag := levelTag(e.Level)
+	if enc.Color && int(e.Level) < len(levelColors) && levelColors[e.Level] != "" {
+		buf = append(buf, levelColors[e.Level]...)
+		buf = append(buf, tag...)
+		buf = append(buf, colorReset...)
+	} else {
+		buf = append(buf, tag...)
+	}
+	// Pad to the longest tag [DEBUG].
+	for i := len(tag); i < len("[DEBUG]")+1; i++ {
+		buf = append(buf, ' ')
+	}
+	if e.Caller != "" {
+		width := enc.CallerWidth
+		if width <= 0 {
+			width = defaultCallerWidth
+		}
+		if enc.Color {
+			buf = append(buf, colorGray...)
+		}
+		buf = append(buf, e.Caller...)
+		if e.Function != "" {
+			buf = append(buf, ':')
+			buf = append(buf, e.Function...)
+		}
+		if enc.Color {
+			buf = append(buf, colorReset...)
+		}
+		n := len(e.Caller)
+		if e.Function != "" {
+			n += len(e.Function) + 1
+		}
+		for ; n < width; n++ {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, ' ')
+	}
+	if e.Component != "" {
+		buf = append(buf, '[')
+		buf = append(buf, e.Component...)
+		buf = append(buf, "] "...)
+	}
+	buf = appendErrorMessage(buf, e, appendString)
+	for i := range e.Fields {
+		buf = append(buf, ' ')
+		if enc.Color {
+			buf = append(buf, colorGray...)
+			buf = appendFieldKey(buf, e.Fields[i].Key)
+			buf = append(buf, '=')
+			buf = append(buf, colorReset...)
+		} else {
+			buf = appendFieldKey(buf, e.Fields[i].Key)
+			buf = append(buf, '=')
+		}
+		buf = appendTextValue(buf, &e.Fields[i])
+	}
+	buf = append(buf, '\n')
+	for _, frame := range e.Stack {
+		buf = append(buf, '\t')
+		buf = append(buf, frame...)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// NewConsoleSink create a sink write to terminal f with ConsoleEncoder,
+// like os.Stderr. Color is turned on if ColorEnabled(f).
+func NewConsoleSink(f *os.File) *WriterSink {
+	// Hide Sync of f, sync a terminal is an error.
+	return NewWriterSink(struct{ io.Writer }{f}, ConsoleEncoder{Color: ColorEnabled(f)})
+}
+
+// ColorEnabled report whether color should be used for output to f.
+// NO_COLOR turns color off, FORCE_COLOR turns it on,
+// otherwise color is used if f is a terminal.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch os.Getenv("FORCE_COLOR") {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	return isTerminal(f)
+}
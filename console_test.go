@@ -0,0 +1,86 @@
+package zlogger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConsoleEncoder(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_console", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	var plain, color bytes.Buffer
+	l.AddSink(NewWriterSink(&plain, ConsoleEncoder{}))
+	l.AddSink(NewWriterSink(&color, ConsoleEncoder{Color: true, CallerWidth: 30}))
+	l.Named("db").InfoW("connected", String("host", "local host"), Int("port", 3306))
+	l.Error("query failed.", errors.New("timeout"))
+
+	lines := strings.Split(plain.String(), "\n")
+	if !strings.Contains(lines[0], " [INFO]  console_test.go:") ||
+		!strings.HasSuffix(lines[0], ` [db] connected host="local host" port=3306`) {
+		t.Error("Console log line is", lines[0])
+	}
+	// Caller column is aligned.
+	if strings.Index(lines[0], "[db]") != strings.Index(lines[1], "query failed.") {
+		t.Error("Caller column is not aligned", lines[0], lines[1])
+	}
+	if !strings.Contains(color.String(), colorGreen+"[INFO]"+colorReset) ||
+		!strings.Contains(color.String(), colorRed+"[ERROR]"+colorReset) {
+		t.Error("Console log is not colored", color.String())
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	if ColorEnabled(f) {
+		t.Error("Regular file should not be colored")
+	}
+	if null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		if ColorEnabled(null) {
+			t.Error("Null device should not be colored")
+		}
+		_ = null.Close()
+	}
+	t.Setenv("FORCE_COLOR", "1")
+	if !ColorEnabled(f) {
+		t.Error("FORCE_COLOR should turn color on")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(f) {
+		t.Error("NO_COLOR should turn color off")
+	}
+}
+
+func TestConsoleFieldKey(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_console_key", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	var plain, color bytes.Buffer
+	l.AddSink(NewWriterSink(&plain, ConsoleEncoder{}))
+	l.AddSink(NewWriterSink(&color, ConsoleEncoder{Color: true}))
+	l.InfoW("login", String("user\n[ERROR] forged", "bob"))
+	for _, out := range []string{plain.String(), color.String()} {
+		if strings.Count(out, "\n") != 1 || strings.Contains(out, "user\n") {
+			t.Error("Field key forges a line", out)
+		}
+	}
+}
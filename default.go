@@ -0,0 +1,59 @@
+package zlogger
+
+import (
+	"time"
+)
+
+// retireGracePeriod is the longest time to wait in-flight calls on
+// a replaced default logger before close it.
+const retireGracePeriod = 5 * time.Second
+
+// SetDefault replace default logger used by package level functions.
+// In-flight calls finish against the old logger, and old logger is not closed.
+// Call restore to set the old logger back, e.g. at the end of a test.
+func SetDefault(l *Logger) (restore func()) {
+	defaultMutex.Lock()
+	old := defaultLogger.Swap(l)
+	defaultMutex.Unlock()
+	return func() {
+		defaultMutex.Lock()
+		defaultLogger.Store(old)
+		defaultMutex.Unlock()
+	}
+}
+
+// acquireDefaultLogger get default logger and mark it in use until release.
+// A replaced logger is never returned, so it can be closed when refs drop to 0.
+func acquireDefaultLogger() *Logger {
+	for {
+		l := getDefaultLogger()
+		l.refs.Add(1)
+		if defaultLogger.Load() == l {
+			return l
+		}
+		l.refs.Add(-1)
+	}
+}
+
+// release mark logger not in use, do nothing for nil.
+func (logger *Logger) release() {
+	if logger != nil {
+		logger.refs.Add(-1)
+	}
+}
+
+// retire close a replaced default logger once it is no longer referenced,
+// or after retireGracePeriod.
+func (logger *Logger) retire() {
+	if logger.refs.Load() == 0 {
+		logger.Close()
+		return
+	}
+	go func() {
+		deadline := time.Now().Add(retireGracePeriod)
+		for logger.refs.Load() > 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		logger.Close()
+	}()
+}
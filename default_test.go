@@ -0,0 +1,59 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetDefault(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_default", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	old := getDefaultLogger()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			Info("concurrent with set default", i)
+		}
+	}()
+	restore := SetDefault(l)
+	if getDefaultLogger() != l {
+		t.Error("Default logger is not replaced")
+	}
+	Info("to new default")
+	restore()
+	wg.Wait()
+	if getDefaultLogger() != old {
+		t.Error("Default logger is not restored")
+	}
+	if old.refs.Load() != 0 || l.refs.Load() != 0 {
+		t.Error("Default logger refs leak")
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for {
+		line := readLine(reader)
+		if line == "" {
+			t.Fatal("Log to new default not found")
+		}
+		if strings.Contains(line, "[INFO] to new default") {
+			break
+		}
+	}
+}
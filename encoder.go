@@ -0,0 +1,242 @@
+package zlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Entry is a log entry before encoded.
+type Entry struct {
+	Level        uint8     // The level of log
+	Time         time.Time // The time of log
+	Caller       string    // File & line of call point, like file.go:12
+	Function     string    // Function of call point, empty if not enabled
+	Component    string    // Component name of child logger
+	Message      string    // The message of log
+	Stack        []string  // Stacktrace of call point, one frame per item
+	Errors       []error   // Error values split from message
+	ErrorOffsets []int     // Offsets of Errors in Message, text encoders write them there
+	Fields       []Field   // Typed fields of entry
+}
+
+// Encoder encode log entry to bytes.
+type Encoder interface {
+	// Encode append encoded entry to buf, end with newline.
+	Encode(buf []byte, e *Entry) []byte
+}
+
+// levelTags is level tag of text log.
+var levelTags = [...]string{
+	LogLevelAll:   "[ALL]",
+	LogLevelDebug: "[DEBUG]",
+	LogLevelInfo:  "[INFO]",
+	LogLevelWarn:  "[WARN]",
+	LogLevelError: "[ERROR]",
+	LogLevelFatal: "[FATAL]",
+	LogLevelPanic: "[PANIC]",
+	LogLevelOff:   "[OFF]",
+}
+
+func levelTag(level uint8) string {
+	if int(level) < len(levelTags) {
+		return levelTags[level]
+	}
+	return "[UNKNOWN]"
+}
+
+// TextEncoder is the default encoder, like:
+// 2006/01/02 15:04:05.000000 file.go:12: [INFO] [component] message key=value
+// Stacktrace follows in indented lines.
+// Field values are always quoted & escaped if they have space or control chars,
+// Multiline decides how newlines of message are written.
+type TextEncoder struct {
+	Multiline    uint8  // MultilineRaw, MultilineEscape or MultilineIndent
+	IndentMarker string // Marker of continuation lines, empty is defaultIndentMarker
+}
+
+func (enc TextEncoder) Encode(buf []byte, e *Entry) []byte {
+	buf = e.Time.AppendFormat(buf, "2006/01/02 15:04:05.000000")
+	buf = append(buf, ' ')
+	if e.Caller != "" {
+		buf = append(buf, e.Caller...)
+		buf = append(buf, ':')
+		if e.Function != "" {
+			buf = append(buf, e.Function...)
+			buf = append(buf, ':')
+		}
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, levelTag(e.Level)...)
+	if e.Component != "" {
+		buf = append(buf, " ["...)
+		buf = append(buf, e.Component...)
+		buf = append(buf, ']')
+	}
+	buf = append(buf, ' ')
+	buf = appendErrorMessage(buf, e, enc.appendMessage)
+	for i := range e.Fields {
+		buf = append(buf, ' ')
+		buf = appendFieldKey(buf, e.Fields[i].Key)
+		buf = append(buf, '=')
+		buf = appendTextValue(buf, &e.Fields[i])
+	}
+	buf = append(buf, '\n')
+	for _, frame := range e.Stack {
+		buf = append(buf, '\t')
+		buf = append(buf, frame...)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// JSONEncoder encode entry as one JSON object per line, like:
+// {"time":"...","level":"info","caller":"file.go:12","logger":"component","msg":"message","key":"value"}
+type JSONEncoder struct{}
+
+func (JSONEncoder) Encode(buf []byte, e *Entry) []byte {
+	buf = append(buf, `{"time":"`...)
+	buf = e.Time.AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	buf = append(buf, `","level":"`...)
+	buf = append(buf, LogLevel2Str(e.Level)...)
+	buf = append(buf, '"')
+	if e.Caller != "" {
+		buf = append(buf, `,"caller":`...)
+		buf = appendJSONString(buf, e.Caller)
+	}
+	if e.Function != "" {
+		buf = append(buf, `,"func":`...)
+		buf = appendJSONString(buf, e.Function)
+	}
+	if e.Component != "" {
+		buf = append(buf, `,"logger":`...)
+		buf = appendJSONString(buf, e.Component)
+	}
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONString(buf, e.Message)
+	if len(e.Errors) == 1 {
+		buf = append(buf, `,"error":`...)
+		buf = appendJSONError(buf, NewErrorInfo(e.Errors[0]))
+	} else if len(e.Errors) > 1 {
+		buf = append(buf, `,"errors":[`...)
+		for i, err := range e.Errors {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONError(buf, NewErrorInfo(err))
+		}
+		buf = append(buf, ']')
+	}
+	for i := range e.Fields {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, e.Fields[i].Key)
+		buf = append(buf, ':')
+		buf = appendJSONField(buf, &e.Fields[i])
+	}
+	if len(e.Stack) > 0 {
+		buf = append(buf, `,"stacktrace":[`...)
+		for i, frame := range e.Stack {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, frame)
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, "}\n"...)
+	return buf
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString append s to buf as a quoted JSON string.
+// Invalid UTF-8 is replaced by U+FFFD.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, "\ufffd"...)
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
+
+// appendJSONValue append v to buf as a JSON value.
+// Value can't be marshaled is appended as string.
+func appendJSONValue(buf []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case string:
+		return appendJSONString(buf, x)
+	case bool:
+		return strconv.AppendBool(buf, x)
+	case int:
+		return strconv.AppendInt(buf, int64(x), 10)
+	case int64:
+		return strconv.AppendInt(buf, x, 10)
+	case uint64:
+		return strconv.AppendUint(buf, x, 10)
+	case float64:
+		return appendJSONFloat(buf, x)
+	case error:
+		return appendJSONString(buf, errorString(x))
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(buf, fmt.Sprint(v))
+	}
+	return append(buf, data...)
+}
+
+// appendJSONFloat append f to buf, NaN & Inf are appended as string.
+func appendJSONFloat(buf []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		buf = append(buf, '"')
+		buf = strconv.AppendFloat(buf, f, 'g', -1, 64)
+		return append(buf, '"')
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, 64)
+}
+
+// SetEncoder set the encoder of log entry.
+// Child loggers follow the encoder of root.
+func (logger *Logger) SetEncoder(enc Encoder) {
+	logger.resolve().root().encoder.Store(&enc)
+}
+
+func (logger *Logger) GetEncoder() Encoder {
+	return *logger.resolve().root().encoder.Load()
+}
+
+func SetEncoder(enc Encoder) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetEncoder(enc)
+}
@@ -0,0 +1,168 @@
+package zlogger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxErrorDepth is the most levels of wrapped errors to record.
+const maxErrorDepth = 32
+
+// ErrorFielder can be implemented by error to expose fields to log.
+type ErrorFielder interface {
+	LogFields() map[string]interface{}
+}
+
+// ErrorInfo is the detail of an error value logged.
+type ErrorInfo struct {
+	Message string                 // Message of error
+	Type    string                 // Concrete type name of error
+	Fields  map[string]interface{} // Fields from ErrorFielder
+	Causes  []*ErrorInfo           // Wrapped errors by errors.Unwrap & errors.Join
+}
+
+// NewErrorInfo get the detail of err, include wrapped chain.
+// Detail of error redacted by logger is redacted too.
+func NewErrorInfo(err error) *ErrorInfo {
+	if r, ok := err.(*redactedError); ok {
+		return r.info
+	}
+	return newErrorInfo(err, 0)
+}
+
+func newErrorInfo(err error, depth int) (info *ErrorInfo) {
+	info = &ErrorInfo{
+		Message: errorString(err),
+		Type:    fmt.Sprintf("%T", err),
+	}
+	defer func() {
+		// Methods of typed nil error may panic, keep message & type only like fmt.
+		if recover() != nil {
+			info.Fields, info.Causes = nil, nil
+		}
+	}()
+	if f, ok := err.(ErrorFielder); ok {
+		info.Fields = f.LogFields()
+	}
+	if depth >= maxErrorDepth {
+		return info
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			info.Causes = []*ErrorInfo{newErrorInfo(cause, depth+1)}
+		}
+	case interface{ Unwrap() []error }:
+		for _, cause := range e.Unwrap() {
+			if cause != nil {
+				info.Causes = append(info.Causes, newErrorInfo(cause, depth+1))
+			}
+		}
+	}
+	return info
+}
+
+// errorString get message of err, fmt handles panic of nil receiver.
+func errorString(err error) string {
+	return fmt.Sprint(err)
+}
+
+// splitErrors format msg like sprintln, error values are split out.
+// offsets are where errors are in message, see Entry.ErrorOffsets.
+func splitErrors(msg []interface{}) (s string, errs []error, offsets []int) {
+	found := false
+	for _, m := range msg {
+		if _, ok := m.(error); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return sprintln(msg), nil, nil
+	}
+	var buf []byte
+	n := 0
+	for _, m := range msg {
+		if err, ok := m.(error); ok {
+			errs = append(errs, err)
+			offsets = append(offsets, len(buf))
+			continue
+		}
+		if red, ok := m.(Redactor); ok {
+			m = red.Redact()
+		}
+		// Spaces are always added between operands like fmt.Sprintln.
+		if n > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = fmt.Append(buf, m)
+		n++
+	}
+	return string(buf), errs, offsets
+}
+
+// appendErrorMessage append message of e with errors at their places
+// in arguments, like fmt.Sprintln without the newline.
+// appendText append a part of text, like escaping it.
+func appendErrorMessage(buf []byte, e *Entry, appendText func(buf []byte, s string) []byte) []byte {
+	if len(e.Errors) == 0 {
+		return appendText(buf, e.Message)
+	}
+	pos, wrote := 0, false
+	for i, err := range e.Errors {
+		offset := len(e.Message)
+		if i < len(e.ErrorOffsets) && e.ErrorOffsets[i] >= pos && e.ErrorOffsets[i] < offset {
+			offset = e.ErrorOffsets[i]
+		}
+		if offset > pos {
+			buf = appendText(buf, e.Message[pos:offset])
+			wrote = true
+		}
+		if wrote {
+			buf = append(buf, ' ')
+		}
+		buf = appendText(buf, errorString(err))
+		pos, wrote = offset, true
+	}
+	if pos == 0 && e.Message != "" {
+		// Errors are before all other arguments.
+		buf = append(buf, ' ')
+	}
+	return appendText(buf, e.Message[pos:])
+}
+
+// appendJSONError append info as a JSON object to buf.
+func appendJSONError(buf []byte, info *ErrorInfo) []byte {
+	buf = append(buf, `{"msg":`...)
+	buf = appendJSONString(buf, info.Message)
+	buf = append(buf, `,"type":`...)
+	buf = appendJSONString(buf, info.Type)
+	if len(info.Fields) > 0 {
+		keys := make([]string, 0, len(info.Fields))
+		for k := range info.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf = append(buf, `,"fields":{`...)
+		for i, k := range keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, k)
+			buf = append(buf, ':')
+			buf = appendJSONValue(buf, info.Fields[k])
+		}
+		buf = append(buf, '}')
+	}
+	if len(info.Causes) > 0 {
+		buf = append(buf, `,"causes":[`...)
+		for i, cause := range info.Causes {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONError(buf, cause)
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, '}')
+}
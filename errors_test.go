@@ -0,0 +1,188 @@
+package zlogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+type fieldError struct {
+	code int
+}
+
+func (e *fieldError) Error() string {
+	return fmt.Sprintf("field error %d", e.code)
+}
+
+func (e *fieldError) LogFields() map[string]interface{} {
+	return map[string]interface{}{"code": e.code, "retry": true}
+}
+
+func TestErrorInfo(t *testing.T) {
+	base := &fieldError{code: 42}
+	wrapped := fmt.Errorf("query failed: %w", base)
+	joined := errors.Join(wrapped, errors.New("second"))
+	info := NewErrorInfo(joined)
+	if info.Type != "*errors.joinError" || len(info.Causes) != 2 {
+		t.Fatal("Joined error info is", info)
+	}
+	cause := info.Causes[0]
+	if cause.Type != "*fmt.wrapError" || len(cause.Causes) != 1 {
+		t.Fatal("Wrapped error info is", cause)
+	}
+	cause = cause.Causes[0]
+	if cause.Type != "*zlogger.fieldError" || cause.Message != "field error 42" ||
+		cause.Fields["code"] != 42 {
+		t.Error("Base error info is", cause)
+	}
+}
+
+func TestErrorLog(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_error", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	queryErr := fmt.Errorf("query failed: %w", &fieldError{code: 7})
+	l.Error("load user failed.", queryErr)
+	l.SetEncoder(JSONEncoder{})
+	l.Error("load user failed.", queryErr)
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	line := readLine(reader)
+	if !strings.HasSuffix(line, "[ERROR] load user failed. query failed: field error 7") {
+		t.Error("Text error log line is", line)
+	}
+	line = readLine(reader)
+	var entry struct {
+		Msg   string `json:"msg"`
+		Error struct {
+			Msg    string `json:"msg"`
+			Type   string `json:"type"`
+			Causes []struct {
+				Type   string                 `json:"type"`
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"causes"`
+		} `json:"error"`
+	}
+	if err = json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatal("Decode JSON log line failed.", err, line)
+	}
+	if entry.Msg != "load user failed." || entry.Error.Type != "*fmt.wrapError" ||
+		len(entry.Error.Causes) != 1 || entry.Error.Causes[0].Fields["code"] != float64(7) {
+		t.Error("JSON error log line is", line)
+	}
+}
+
+func TestErrorOrder(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_error_order", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	diskErr := errors.New("disk full")
+	l.Error(diskErr, "while saving", 42)
+	l.Error("save", diskErr, "retry", 3)
+	l.Error("save", 3, diskErr, diskErr)
+	if err = l.AddRedactPattern(`\d+`, "N"); err != nil {
+		t.Fatal(err)
+	}
+	l.Error("save", 3, diskErr, "retry", 4)
+	l.SetEncoder(JSONEncoder{})
+	l.Error(diskErr, "while saving", 42)
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, expect := range []string{
+		"[ERROR] disk full while saving 42",
+		"[ERROR] save disk full retry 3",
+		"[ERROR] save 3 disk full disk full",
+		"[ERROR] save N disk full retry N",
+	} {
+		if line := readLine(reader); !strings.HasSuffix(line, expect) {
+			t.Error("Text error log line is", line, "not", expect)
+		}
+	}
+	line := readLine(reader)
+	var entry struct {
+		Msg   string `json:"msg"`
+		Error struct {
+			Msg string `json:"msg"`
+		} `json:"error"`
+	}
+	if err = json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatal("Decode JSON log line failed.", err, line)
+	}
+	if entry.Msg != "while saving N" || entry.Error.Msg != "disk full" {
+		t.Error("JSON error log line is", line)
+	}
+}
+
+type nilWrapError struct {
+	cause error
+}
+
+func (e *nilWrapError) Error() string {
+	return "wrap: " + e.cause.Error()
+}
+
+func (e *nilWrapError) Unwrap() error {
+	return e.cause
+}
+
+func TestErrorTypedNil(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_error_nil", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	l.SetEncoder(JSONEncoder{})
+	l.AddRedactFields("code")
+	l.Error("failed", (*nilWrapError)(nil))
+	l.Error("failed", (*fieldError)(nil))
+	l.ErrorW("failed", Err((*fieldError)(nil)))
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, typ := range []string{"*zlogger.nilWrapError", "*zlogger.fieldError", "*zlogger.fieldError"} {
+		line := readLine(reader)
+		var entry struct {
+			Msg   string `json:"msg"`
+			Error struct {
+				Type string `json:"type"`
+			} `json:"error"`
+		}
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal("Decode JSON log line failed.", err, line)
+		}
+		if entry.Msg != "failed" || entry.Error.Type != typ {
+			t.Error("Typed nil error log line is", line)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package zlogger
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Type of Field value.
+const (
+	FieldString   = 0
+	FieldInt      = 1
+	FieldUint     = 2
+	FieldFloat    = 3
+	FieldBool     = 4
+	FieldDuration = 5
+	FieldTime     = 6
+	FieldError    = 7
+	FieldAny      = 8
+)
+
+// Field is a typed key-value of log entry.
+// Use String, Int & co to create it, value is not boxed to interface{}.
+type Field struct {
+	Key       string
+	Type      uint8
+	Integer   int64       // Value of int, uint, float bits, bool, duration & time
+	String    string      // Value of string
+	Interface interface{} // Value of error & any, location of time
+}
+
+func String(key, value string) Field {
+	return Field{Key: key, Type: FieldString, String: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: FieldInt, Integer: int64(value)}
+}
+
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: FieldInt, Integer: value}
+}
+
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: FieldUint, Integer: int64(value)}
+}
+
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FieldFloat, Integer: int64(math.Float64bits(value))}
+}
+
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, Type: FieldBool}
+	if value {
+		f.Integer = 1
+	}
+	return f
+}
+
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: FieldDuration, Integer: int64(value)}
+}
+
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: FieldTime, Integer: value.UnixNano(), Interface: value.Location()}
+}
+
+// Err create a field of error with key "error".
+func Err(err error) Field {
+	return Field{Key: "error", Type: FieldError, Interface: err}
+}
+
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Type: FieldError, Interface: err}
+}
+
+// Any create a field of any value, it is boxed to interface{}.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: FieldAny, Interface: value}
+}
+
+// Value get value of field as interface{}.
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case FieldString:
+		return f.String
+	case FieldInt:
+		return f.Integer
+	case FieldUint:
+		return uint64(f.Integer)
+	case FieldFloat:
+		return math.Float64frombits(uint64(f.Integer))
+	case FieldBool:
+		return f.Integer == 1
+	case FieldDuration:
+		return time.Duration(f.Integer)
+	case FieldTime:
+		return f.time()
+	default:
+		return f.Interface
+	}
+}
+
+func (f Field) time() time.Time {
+	t := time.Unix(0, f.Integer)
+	if loc, ok := f.Interface.(*time.Location); ok && loc != nil {
+		t = t.In(loc)
+	}
+	return t
+}
+
+// LogNW log msg with typed fields at level, n is the depth of caller like DebugN.
+// Fields are not boxed, there is no allocation if level is disabled,
+// unlike arguments of Debug & co.
+func (logger *Logger) LogNW(level uint8, n int, msg string, fields ...Field) {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(level) {
+		return
+	}
+	logger.output(level, n+1, msg, nil, nil, fields)
+	if logger.GetLogLevel() > level {
+		return
+	}
+	switch level {
+	case LogLevelFatal:
+		logger.exit()
+	case LogLevelPanic:
+		panic(msg)
+	}
+}
+
+func (logger *Logger) DebugW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelDebug, 3, msg, fields...)
+}
+
+func (logger *Logger) InfoW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelInfo, 3, msg, fields...)
+}
+
+func (logger *Logger) WarnW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelWarn, 3, msg, fields...)
+}
+
+func (logger *Logger) ErrorW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelError, 3, msg, fields...)
+}
+
+func (logger *Logger) FatalW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelFatal, 3, msg, fields...)
+}
+
+func (logger *Logger) PanicW(msg string, fields ...Field) {
+	logger.LogNW(LogLevelPanic, 3, msg, fields...)
+}
+
+func DebugW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelDebug, 3, msg, fields...)
+}
+
+func InfoW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelInfo, 3, msg, fields...)
+}
+
+func WarnW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelWarn, 3, msg, fields...)
+}
+
+func ErrorW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelError, 3, msg, fields...)
+}
+
+func FatalW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelFatal, 3, msg, fields...)
+}
+
+func PanicW(msg string, fields ...Field) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNW(LogLevelPanic, 3, msg, fields...)
+}
+
+// appendTextValue append value of field to buf for text log.
+// String which has space, quote, '=' or control char is quoted.
+func appendTextValue(buf []byte, f *Field) []byte {
+	return appendFieldValue(buf, f, appendTextString)
+}
+
+// appendFieldValue append value of field to buf, strings are appended by appendString.
+func appendFieldValue(buf []byte, f *Field, appendString func([]byte, string) []byte) []byte {
+	switch f.Type {
+	case FieldString:
+		return appendString(buf, f.String)
+	case FieldInt:
+		return strconv.AppendInt(buf, f.Integer, 10)
+	case FieldUint:
+		return strconv.AppendUint(buf, uint64(f.Integer), 10)
+	case FieldFloat:
+		return strconv.AppendFloat(buf, math.Float64frombits(uint64(f.Integer)), 'g', -1, 64)
+	case FieldBool:
+		return strconv.AppendBool(buf, f.Integer == 1)
+	case FieldDuration:
+		return append(buf, time.Duration(f.Integer).String()...)
+	case FieldTime:
+		return f.time().AppendFormat(buf, time.RFC3339Nano)
+	case FieldError:
+		err, _ := f.Interface.(error)
+		return appendString(buf, errorString(err))
+	default:
+		return appendString(buf, fmt.Sprint(f.Interface))
+	}
+}
+
+func appendTextString(buf []byte, s string) []byte {
+	if needQuote(s) {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+// appendFieldKey append key to buf, invalid chars of key are replaced by '_'.
+func appendFieldKey(buf []byte, key string) []byte {
+	if key == "" {
+		return append(buf, '_')
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+func needQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return true
+		}
+	}
+	return !utf8.ValidString(s)
+}
+
+// appendJSONField append value of field to buf as a JSON value.
+func appendJSONField(buf []byte, f *Field) []byte {
+	switch f.Type {
+	case FieldString:
+		return appendJSONString(buf, f.String)
+	case FieldInt:
+		return strconv.AppendInt(buf, f.Integer, 10)
+	case FieldUint:
+		return strconv.AppendUint(buf, uint64(f.Integer), 10)
+	case FieldFloat:
+		return appendJSONFloat(buf, math.Float64frombits(uint64(f.Integer)))
+	case FieldBool:
+		return strconv.AppendBool(buf, f.Integer == 1)
+	case FieldDuration:
+		return appendJSONString(buf, time.Duration(f.Integer).String())
+	case FieldTime:
+		buf = append(buf, '"')
+		buf = f.time().AppendFormat(buf, time.RFC3339Nano)
+		return append(buf, '"')
+	case FieldError:
+		err, _ := f.Interface.(error)
+		return appendJSONError(buf, NewErrorInfo(err))
+	default:
+		return appendJSONValue(buf, f.Interface)
+	}
+}
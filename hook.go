@@ -0,0 +1,61 @@
+package zlogger
+
+// Hook is called with every entry before it is written, after redaction.
+// Entries below log level kept by flight recorder are not passed to hooks.
+// Hooks run in registration order, and may be called concurrently.
+// Hook can modify the entry, or return false to drop it.
+// Entry is reused after written, call Clone to keep it.
+type Hook interface {
+	Fire(e *Entry) bool
+}
+
+// HookFunc is a function used as Hook.
+type HookFunc func(e *Entry) bool
+
+func (f HookFunc) Fire(e *Entry) bool {
+	return f(e)
+}
+
+// AddHook add hook to logger.
+// Child loggers run hooks of root.
+func (logger *Logger) AddHook(hook Hook) {
+	root := logger.resolve().root()
+	root.hookMutex.Lock()
+	defer root.hookMutex.Unlock()
+	var hooks []Hook
+	if old := root.hooks.Load(); old != nil {
+		hooks = append(hooks, *old...)
+	}
+	hooks = append(hooks, hook)
+	root.hooks.Store(&hooks)
+}
+
+// fireHooks run hooks with entry, return false if entry is dropped.
+func (logger *Logger) fireHooks(e *Entry) bool {
+	hooks := logger.hooks.Load()
+	if hooks == nil {
+		return true
+	}
+	for _, hook := range *hooks {
+		if !hook.Fire(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone copy entry, the copy can be kept after entry is written.
+func (e *Entry) Clone() *Entry {
+	c := *e
+	c.Stack = append([]string(nil), e.Stack...)
+	c.Errors = append([]error(nil), e.Errors...)
+	c.ErrorOffsets = append([]int(nil), e.ErrorOffsets...)
+	c.Fields = append([]Field(nil), e.Fields...)
+	return &c
+}
+
+func AddHook(hook Hook) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.AddHook(hook)
+}
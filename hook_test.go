@@ -0,0 +1,66 @@
+package zlogger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHook(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_hook", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	var out bytes.Buffer
+	l.AddSink(NewWriterSink(&out, TextEncoder{}))
+
+	var order []string
+	var orderMutex sync.Mutex
+	var count atomic.Int64
+	alerts := make(chan *Entry, 10)
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		orderMutex.Lock()
+		order = append(order, "host")
+		orderMutex.Unlock()
+		e.Fields = append(e.Fields, String("host", "web-1"))
+		return true
+	}))
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		orderMutex.Lock()
+		order = append(order, "drop")
+		orderMutex.Unlock()
+		return !strings.Contains(e.Message, "health check")
+	}))
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		count.Add(1)
+		if e.Level >= LogLevelError {
+			alerts <- e.Clone()
+		}
+		return true
+	}))
+	l.Info("health check")
+	l.Named("db").Error("connection lost")
+
+	if strings.Join(order, ",") != "host,drop,host,drop" {
+		t.Error("Hook order is", order)
+	}
+	if count.Load() != 1 {
+		t.Error("Hook after veto is called", count.Load(), "times")
+	}
+	if out.String() == "" || strings.Contains(out.String(), "health check") ||
+		!strings.HasSuffix(out.String(), "[ERROR] [db] connection lost host=web-1\n") {
+		t.Error("Hooked log is", out.String())
+	}
+	alert := <-alerts
+	if alert.Message != "connection lost" || alert.Component != "db" ||
+		!strings.HasPrefix(alert.Caller, "hook_test.go:") || len(alert.Fields) != 1 {
+		t.Error("Alert entry is", alert)
+	}
+}
@@ -0,0 +1,177 @@
+package zlogger
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// defaultJournalAddress is the native protocol socket of systemd-journald.
+const defaultJournalAddress = "/run/systemd/journal/socket"
+
+var (
+	ErrJournalNotSupported = errors.New("large journal entry is not supported on this platform")
+)
+
+// JournalConfig is the config of journald sink.
+type JournalConfig struct {
+	Address    string // Socket of journald, default is defaultJournalAddress
+	Identifier string // SYSLOG_IDENTIFIER of entries, default is Logger.Name
+}
+
+// JournalSink send entries to systemd-journald by native protocol.
+// Entry has fields MESSAGE, PRIORITY, CODE_FILE, CODE_LINE, CODE_FUNC,
+// SYSLOG_IDENTIFIER, LOGGER (component), STACKTRACE & custom fields.
+// Names of custom fields are upper cased, invalid chars are replaced by '_'.
+// Entry too large for a datagram is sent by a sealed memfd.
+type JournalSink struct {
+	config JournalConfig
+	addr   *net.UnixAddr
+	mutex  sync.Mutex
+	conn   *net.UnixConn // Unconnected, journald can be restarted
+}
+
+// NewJournalSink create a journald sink, connect to journald lazily.
+func NewJournalSink(config JournalConfig) *JournalSink {
+	if config.Address == "" {
+		config.Address = defaultJournalAddress
+	}
+	if config.Identifier == "" {
+		config.Identifier = "zlogger"
+	}
+	return &JournalSink{config: config, addr: &net.UnixAddr{Name: config.Address, Net: "unixgram"}}
+}
+
+// AddJournalSink create a journald sink & add it to logger.
+// Identifier of config is default to Logger.Name.
+func (logger *Logger) AddJournalSink(config JournalConfig) *JournalSink {
+	logger = logger.resolve().root()
+	if config.Identifier == "" {
+		config.Identifier = logger.Name
+	}
+	sink := NewJournalSink(config)
+	logger.AddSink(sink)
+	return sink
+}
+
+func (s *JournalSink) Write(e *Entry) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = s.appendEntry(*buf, e)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	_, err := s.conn.WriteToUnix(*buf, s.addr)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		// Too large for a datagram, send it by a file descriptor.
+		return sendJournalFd(s.conn, s.addr, *buf)
+	}
+	return err
+}
+
+// appendEntry append entry as fields of journal native protocol.
+func (s *JournalSink) appendEntry(buf []byte, e *Entry) []byte {
+	msg := getBuffer()
+	*msg = appendErrorMessage(*msg, e, appendString)
+	buf = appendJournalField(buf, "MESSAGE", *msg)
+	putBuffer(msg)
+	severity := 7
+	if int(e.Level) < len(syslogSeverities) {
+		severity = syslogSeverities[e.Level]
+	}
+	buf = append(buf, "PRIORITY="...)
+	buf = append(buf, byte('0'+severity), '\n')
+	if e.Caller != "" {
+		file, line := e.Caller, ""
+		if i := strings.LastIndexByte(e.Caller, ':'); i >= 0 {
+			file, line = e.Caller[:i], e.Caller[i+1:]
+		}
+		buf = appendJournalField(buf, "CODE_FILE", []byte(file))
+		if line != "" {
+			buf = appendJournalField(buf, "CODE_LINE", []byte(line))
+		}
+	}
+	if e.Function != "" {
+		buf = appendJournalField(buf, "CODE_FUNC", []byte(e.Function))
+	}
+	buf = appendJournalField(buf, "SYSLOG_IDENTIFIER", []byte(s.config.Identifier))
+	if e.Component != "" {
+		buf = appendJournalField(buf, "LOGGER", []byte(e.Component))
+	}
+	if len(e.Stack) > 0 {
+		buf = appendJournalField(buf, "STACKTRACE", []byte(strings.Join(e.Stack, "\n")))
+	}
+	value := getBuffer()
+	defer putBuffer(value)
+	for i := range e.Fields {
+		*value = appendFieldValue((*value)[:0], &e.Fields[i], appendString)
+		buf = appendJournalField(buf, journalFieldName(e.Fields[i].Key), *value)
+	}
+	return buf
+}
+
+// appendJournalField append a field as NAME=value\n,
+// value has newline is appended as NAME\n<little endian uint64 size>value\n.
+func appendJournalField(buf []byte, name string, value []byte) []byte {
+	buf = append(buf, name...)
+	for _, c := range value {
+		if c == '\n' {
+			buf = append(buf, '\n')
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(len(value)))
+			buf = append(buf, value...)
+			return append(buf, '\n')
+		}
+	}
+	buf = append(buf, '=')
+	buf = append(buf, value...)
+	return append(buf, '\n')
+}
+
+// journalFieldName convert key to a valid journal field name:
+// up to 64 chars of A-Z, 0-9 & '_', not starting with '_' or digit.
+func journalFieldName(key string) string {
+	name := make([]byte, 0, len(key)+1)
+	if key == "" || key[0] == '_' || (key[0] >= '0' && key[0] <= '9') {
+		name = append(name, 'F')
+		if key == "" || key[0] != '_' {
+			name = append(name, '_')
+		}
+	}
+	for i := 0; i < len(key) && len(name) < 64; i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+		name = append(name, c)
+	}
+	return string(name)
+}
+
+// Sync do nothing, journal is not buffered.
+func (s *JournalSink) Sync() error {
+	return nil
+}
+
+func (s *JournalSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,71 @@
+package zlogger
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Flags of memfd_create & fcntl seals.
+const (
+	mfdCloexec      = 0x1
+	mfdAllowSealing = 0x2
+	fAddSeals       = 1033
+	fSealSeal       = 0x1
+	fSealShrink     = 0x2
+	fSealGrow       = 0x4
+	fSealWrite      = 0x8
+)
+
+// sendJournalFd write data to a sealed memfd & send fd to journald.
+// A deleted file of /dev/shm is used if memfd is not available.
+func sendJournalFd(conn *net.UnixConn, addr *net.UnixAddr, data []byte) error {
+	file, err := memfdCreate("zlogger-journal")
+	if err == nil {
+		if _, err = file.Write(data); err == nil {
+			_, err = fcntl(file.Fd(), fAddSeals, fSealSeal|fSealShrink|fSealGrow|fSealWrite)
+		}
+		if err != nil {
+			_ = file.Close()
+			file = nil
+		}
+	}
+	if file == nil {
+		file, err = os.CreateTemp("/dev/shm", "zlogger-journal-")
+		if err != nil {
+			return err
+		}
+		_ = os.Remove(file.Name())
+		if _, err = file.Write(data); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	defer func() { _ = file.Close() }()
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), addr)
+	return err
+}
+
+func memfdCreate(name string) (*os.File, error) {
+	if sysMemfdCreate == 0 {
+		return nil, syscall.ENOSYS
+	}
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	fd, _, errno := syscall.Syscall(sysMemfdCreate, uintptr(unsafe.Pointer(p)), mfdCloexec|mfdAllowSealing, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	return os.NewFile(fd, name), nil
+}
+
+func fcntl(fd uintptr, cmd, arg int) (int, error) {
+	r, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, uintptr(cmd), uintptr(arg))
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(r), nil
+}
@@ -0,0 +1,123 @@
+package zlogger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// readJournal read an entry sent to journald socket conn, from datagram or fd.
+func readJournal(t *testing.T, conn *net.UnixConn) map[string]string {
+	buf := make([]byte, 64<<10)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal("Read journal entry failed.", err)
+	}
+	data := buf[:n]
+	if oobn > 0 {
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil || len(msgs) != 1 {
+			t.Fatal("Parse control message failed.", err)
+		}
+		fds, err := syscall.ParseUnixRights(&msgs[0])
+		if err != nil || len(fds) != 1 {
+			t.Fatal("Parse fd failed.", err)
+		}
+		file := os.NewFile(uintptr(fds[0]), "journal")
+		defer func() { _ = file.Close() }()
+		if data, err = io.ReadAll(io.NewSectionReader(file, 0, 1<<30)); err != nil {
+			t.Fatal("Read fd failed.", err)
+		}
+	}
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		i := bytes.IndexAny(data, "=\n")
+		if i < 0 {
+			t.Fatal("Broken entry", string(data))
+		}
+		name := string(data[:i])
+		if data[i] == '=' {
+			end := bytes.IndexByte(data, '\n')
+			fields[name] = string(data[i+1 : end])
+			data = data[end+1:]
+			continue
+		}
+		size := int(binary.LittleEndian.Uint64(data[i+1:]))
+		fields[name] = string(data[i+9 : i+9+size])
+		data = data[i+10+size:]
+	}
+	return fields
+}
+
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skip("Can't listen unixgram.", err)
+	}
+	return conn, path
+}
+
+func TestJournalSink(t *testing.T) {
+	conn, path := listenJournal(t)
+	defer func() { _ = conn.Close() }()
+	l, err := NewInternal("./", "zlogger_journal", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	l.AddJournalSink(JournalConfig{Address: path})
+	l.Named("db").ErrorW("two\nlines", String("user.id", "u1"), Int("_retry", 2), Int("9x", 3))
+
+	fields := readJournal(t, conn)
+	want := map[string]string{
+		"MESSAGE":           "two\nlines",
+		"PRIORITY":          "3",
+		"CODE_FILE":         "journald_linux_test.go",
+		"SYSLOG_IDENTIFIER": "zlogger_journal",
+		"LOGGER":            "db",
+		"USER_ID":           "u1",
+		"F_RETRY":           "2",
+		"F_9X":              "3",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("Field %s is %q, want %q", k, fields[k], v)
+		}
+	}
+	if fields["CODE_LINE"] == "" {
+		t.Error("CODE_LINE is missing", fields)
+	}
+}
+
+func TestJournalSinkLarge(t *testing.T) {
+	conn, path := listenJournal(t)
+	defer func() { _ = conn.Close() }()
+	sink := NewJournalSink(JournalConfig{Address: path, Identifier: "app"})
+	defer func() { _ = sink.Close() }()
+	msg := strings.Repeat("x", 1<<20)
+	err := sink.Write(&Entry{Level: LogLevelInfo, Time: time.Now(), Message: msg})
+	if errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.ENOENT) {
+		t.Skip("Can't create memfd or file of /dev/shm.", err)
+	}
+	if err != nil {
+		t.Fatal("Write large entry failed.", err)
+	}
+	fields := readJournal(t, conn)
+	if fields["MESSAGE"] != msg || fields["PRIORITY"] != "6" || fields["SYSLOG_IDENTIFIER"] != "app" {
+		t.Error("Large entry is wrong", len(fields["MESSAGE"]), fields["PRIORITY"])
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package zlogger
+
+import (
+	"net"
+)
+
+func sendJournalFd(_ *net.UnixConn, _ *net.UnixAddr, _ []byte) error {
+	return ErrJournalNotSupported
+}
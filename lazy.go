@@ -0,0 +1,96 @@
+package zlogger
+
+// Lazy is a message evaluated only if the entry is actually written,
+// or kept by flight recorder.
+// Use it as an argument of Debug & co, or a field of Any:
+//
+//	logger.Debug("state:", zlogger.Lazy(func() string { return dump(state) }))
+type Lazy func() string
+
+func (l Lazy) String() string {
+	return l()
+}
+
+// Enabled report whether entry at level will be written.
+// Use it to skip building expensive payloads.
+func (logger *Logger) Enabled(level uint8) bool {
+	return level < LogLevelOff && logger.GetLogLevel() <= level
+}
+
+// LogNFn log message returned by fn at level, fn is only called if level is enabled,
+// or flight recorder is on.
+// n is the depth of caller like DebugN.
+func (logger *Logger) LogNFn(level uint8, n int, fn func() string) {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(level) {
+		return
+	}
+	logger.LogNW(level, n+1, fn())
+}
+
+func (logger *Logger) DebugFn(fn func() string) {
+	logger.LogNFn(LogLevelDebug, 3, fn)
+}
+
+func (logger *Logger) InfoFn(fn func() string) {
+	logger.LogNFn(LogLevelInfo, 3, fn)
+}
+
+func (logger *Logger) WarnFn(fn func() string) {
+	logger.LogNFn(LogLevelWarn, 3, fn)
+}
+
+func (logger *Logger) ErrorFn(fn func() string) {
+	logger.LogNFn(LogLevelError, 3, fn)
+}
+
+func (logger *Logger) FatalFn(fn func() string) {
+	logger.LogNFn(LogLevelFatal, 3, fn)
+}
+
+func (logger *Logger) PanicFn(fn func() string) {
+	logger.LogNFn(LogLevelPanic, 3, fn)
+}
+
+func Enabled(level uint8) bool {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.Enabled(level)
+}
+
+func DebugFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelDebug, 3, fn)
+}
+
+func InfoFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelInfo, 3, fn)
+}
+
+func WarnFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelWarn, 3, fn)
+}
+
+func ErrorFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelError, 3, fn)
+}
+
+func FatalFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelFatal, 3, fn)
+}
+
+func PanicFn(fn func() string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.LogNFn(LogLevelPanic, 3, fn)
+}
@@ -0,0 +1,52 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_lazy", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	if l.Enabled(LogLevelDebug) || !l.Enabled(LogLevelInfo) || l.Enabled(LogLevelOff) {
+		t.Error("Enabled is wrong for level info")
+	}
+	called := 0
+	dump := func() string {
+		called++
+		return "big dump"
+	}
+	l.DebugFn(dump)
+	l.Debug("lazy", Lazy(dump))
+	if called != 0 {
+		t.Error("Lazy message is evaluated for disabled level")
+	}
+	l.InfoFn(dump)
+	l.Info("lazy", Lazy(dump))
+	l.InfoW("lazy field", Any("dump", Lazy(dump)))
+	if called != 3 {
+		t.Error("Lazy message is evaluated", called, "times, not 3")
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, msg := range []string{"[INFO] big dump", "[INFO] lazy big dump",
+		`[INFO] lazy field dump="big dump"`} {
+		line := readLine(reader)
+		if !strings.Contains(line, "lazy_test.go") || !strings.HasSuffix(line, msg) {
+			t.Error("Lazy log line is", line)
+		}
+	}
+}
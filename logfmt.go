@@ -0,0 +1,54 @@
+package zlogger
+
+import (
+	"strings"
+)
+
+// LogfmtEncoder encode entry as logfmt, like:
+// ts=2006-01-02T15:04:05.000000Z07:00 level=info caller=file.go:12 msg="message" key=value
+// Value which has space, quote, '=' or control char is quoted & escaped.
+type LogfmtEncoder struct{}
+
+func (LogfmtEncoder) Encode(buf []byte, e *Entry) []byte {
+	buf = append(buf, "ts="...)
+	buf = e.Time.AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	buf = append(buf, " level="...)
+	buf = append(buf, LogLevel2Str(e.Level)...)
+	if e.Caller != "" {
+		buf = append(buf, " caller="...)
+		buf = appendLogfmtString(buf, e.Caller)
+	}
+	if e.Function != "" {
+		buf = append(buf, " func="...)
+		buf = appendLogfmtString(buf, e.Function)
+	}
+	if e.Component != "" {
+		buf = append(buf, " logger="...)
+		buf = appendLogfmtString(buf, e.Component)
+	}
+	buf = append(buf, " msg="...)
+	buf = appendLogfmtString(buf, e.Message)
+	for _, err := range e.Errors {
+		buf = append(buf, " error="...)
+		buf = appendLogfmtString(buf, errorString(err))
+	}
+	for i := range e.Fields {
+		buf = append(buf, ' ')
+		buf = appendFieldKey(buf, e.Fields[i].Key)
+		buf = append(buf, '=')
+		buf = appendFieldValue(buf, &e.Fields[i], appendLogfmtString)
+	}
+	if len(e.Stack) > 0 {
+		buf = append(buf, " stacktrace="...)
+		buf = appendLogfmtString(buf, strings.Join(e.Stack, "\n"))
+	}
+	return append(buf, '\n')
+}
+
+// appendLogfmtString append s to buf, quote it with JSON escapes if needed.
+func appendLogfmtString(buf []byte, s string) []byte {
+	if needQuote(s) {
+		return appendJSONString(buf, s)
+	}
+	return append(buf, s...)
+}
@@ -0,0 +1,32 @@
+package zlogger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	e := &Entry{
+		Level:     LogLevelWarn,
+		Time:      time.Date(2023, 10, 12, 8, 30, 0, 1000, time.UTC),
+		Caller:    "foo.go:12",
+		Component: "db",
+		Message:   "slow \"query\"\nnext",
+		Errors:    []error{errors.New("timeout")},
+		Fields: []Field{String("sql", "a=b"), Int("rows", 3), String("bad key", ""),
+			String("plain", "ok")},
+	}
+	var buf bytes.Buffer
+	buf.Write(LogfmtEncoder{}.Encode(nil, e))
+	expect := `ts=2023-10-12T08:30:00.000001Z level=warn caller=foo.go:12 logger=db ` +
+		`msg="slow \"query\"\nnext" error=timeout sql="a=b" rows=3 bad_key="" plain=ok` + "\n"
+	if buf.String() != expect {
+		t.Error("Logfmt line is", buf.String())
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Error("Logfmt entry is not one line")
+	}
+}
@@ -0,0 +1,4 @@
+package zlogger
+
+// sysMemfdCreate is the number of memfd_create syscall.
+const sysMemfdCreate = 319
@@ -0,0 +1,6 @@
+package zlogger
+
+import "syscall"
+
+// sysMemfdCreate is the number of memfd_create syscall.
+const sysMemfdCreate = syscall.SYS_MEMFD_CREATE
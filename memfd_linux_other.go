@@ -0,0 +1,6 @@
+//go:build linux && !amd64 && !arm64
+
+package zlogger
+
+// sysMemfdCreate is 0, memfd is not used on this platform.
+const sysMemfdCreate = 0
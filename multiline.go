@@ -0,0 +1,73 @@
+package zlogger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// How newlines & control chars of message are written by TextEncoder.
+const (
+	MultilineRaw    = 0 // Write message as is, only invalid UTF-8 is replaced
+	MultilineEscape = 1 // Escape newlines & control chars, like \n
+	MultilineIndent = 2 // Indent continuation lines with a marker
+)
+
+// defaultIndentMarker is the head of continuation lines of MultilineIndent.
+const defaultIndentMarker = "\t| "
+
+// appendMessage append message to buf by Multiline mode of encoder.
+// Invalid UTF-8 is replaced by U+FFFD in every mode.
+func (enc TextEncoder) appendMessage(buf []byte, s string) []byte {
+	switch enc.Multiline {
+	case MultilineEscape:
+		return appendEscaped(buf, s, "")
+	case MultilineIndent:
+		marker := enc.IndentMarker
+		if marker == "" {
+			marker = defaultIndentMarker
+		}
+		return appendEscaped(buf, s, marker)
+	default:
+		if utf8.ValidString(s) {
+			return append(buf, s...)
+		}
+		return append(buf, strings.ToValidUTF8(s, "\ufffd")...)
+	}
+}
+
+// appendEscaped append s to buf, control chars are escaped.
+// If marker is not empty, newline & tab are kept, newline is followed by marker.
+func appendEscaped(buf []byte, s, marker string) []byte {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				buf = append(buf, "\ufffd"...)
+			} else {
+				buf = append(buf, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		i++
+		switch {
+		case c == '\n' && marker != "":
+			buf = append(buf, '\n')
+			buf = append(buf, marker...)
+		case c == '\t' && marker != "":
+			buf = append(buf, '\t')
+		case c == '\n':
+			buf = append(buf, '\\', 'n')
+		case c == '\r':
+			buf = append(buf, '\\', 'r')
+		case c == '\t':
+			buf = append(buf, '\\', 't')
+		case c < 0x20 || c == 0x7f:
+			buf = append(buf, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,32 @@
+package zlogger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMultiline(t *testing.T) {
+	e := &Entry{
+		Level:   LogLevelInfo,
+		Time:    time.Date(2023, 10, 12, 8, 30, 0, 0, time.Local),
+		Caller:  "foo.go:12",
+		Message: "select *\nfrom t\r\x1b[2J\xff",
+		Fields:  []Field{String("input", "a\nfake [ERROR] line")},
+	}
+	head := "2023/10/12 08:30:00.000000 foo.go:12: [INFO] "
+	field := ` input="a\nfake [ERROR] line"` + "\n"
+	cases := []struct {
+		enc    TextEncoder
+		expect string
+	}{
+		{TextEncoder{}, head + "select *\nfrom t\r\x1b[2J\ufffd" + field},
+		{TextEncoder{Multiline: MultilineEscape}, head + `select *\nfrom t\r\x1b[2J` + "\ufffd" + field},
+		{TextEncoder{Multiline: MultilineIndent}, head + "select *\n\t| from t" + `\r\x1b[2J` + "\ufffd" + field},
+		{TextEncoder{Multiline: MultilineIndent, IndentMarker: "> "}, head + "select *\n> from t" + `\r\x1b[2J` + "\ufffd" + field},
+	}
+	for _, c := range cases {
+		if line := string(c.enc.Encode(nil, e)); line != c.expect {
+			t.Errorf("Multiline mode %d line is %q", c.enc.Multiline, line)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package zlogger
+
+import (
+	"errors"
+)
+
+var (
+	ErrComponentNotFound = errors.New("component not found")
+)
+
+// Named create a child logger of a component.
+// Child logger shares the rotating file of its root logger,
+// and prefix every line with [name] after the level tag.
+// Name of nested child is joined by '.', like "db.pool".
+// Child logger follows log level of its parent until SetLogLevel is called.
+// Call Named with the same name returns the same child logger.
+// Child of logger from Get or package level Named is resolved on every call,
+// so it follows the logger registered or set as default later.
+func (logger *Logger) Named(name string) *Logger {
+	if logger.placeholder {
+		return logger.placeholderChild(name)
+	}
+	if logger.component != "" {
+		name = logger.component + "." + name
+	}
+	root := logger.root()
+	root.compMutex.Lock()
+	defer root.compMutex.Unlock()
+	if child, ok := root.components[name]; ok {
+		return child
+	}
+	child := &Logger{
+		Path:      root.Path,
+		Name:      root.Name,
+		FileName:  root.fileName(),
+		parent:    logger,
+		component: name,
+	}
+	child.logLevel.Store(uint8(LogLevelAll))
+	if root.components == nil {
+		root.components = make(map[string]*Logger)
+	}
+	root.components[name] = child
+	return child
+}
+
+// placeholderChild get child of placeholder, which is a placeholder too.
+// It keeps parent & name, and is resolved as resolve(parent).Named(name).
+func (logger *Logger) placeholderChild(name string) *Logger {
+	logger.compMutex.Lock()
+	defer logger.compMutex.Unlock()
+	if child, ok := logger.components[name]; ok {
+		return child
+	}
+	child := &Logger{parent: logger, component: name, placeholder: true}
+	if logger.components == nil {
+		logger.components = make(map[string]*Logger)
+	}
+	logger.components[name] = child
+	return child
+}
+
+// root get the logger which owns the log file.
+func (logger *Logger) root() *Logger {
+	l := logger
+	for l.parent != nil {
+		l = l.parent
+	}
+	return l
+}
+
+// Component get name of child logger, empty for root logger.
+func (logger *Logger) Component() string {
+	return logger.resolve().component
+}
+
+// ResetLogLevel drop the own log level of child logger,
+// make it follow its parent again.
+func (logger *Logger) ResetLogLevel() {
+	logger.resolve().levelOverride.Store(false)
+}
+
+// Components list effective log level of all child loggers by name.
+func (logger *Logger) Components() map[string]uint8 {
+	root := logger.resolve().root()
+	root.compMutex.Lock()
+	defer root.compMutex.Unlock()
+	levels := make(map[string]uint8, len(root.components))
+	for name, child := range root.components {
+		levels[name] = child.GetLogLevel()
+	}
+	return levels
+}
+
+// SetComponentLogLevel set log level of child logger by name at runtime.
+func (logger *Logger) SetComponentLogLevel(name string, logLevel uint8) error {
+	root := logger.resolve().root()
+	root.compMutex.Lock()
+	child, ok := root.components[name]
+	root.compMutex.Unlock()
+	if !ok {
+		return ErrComponentNotFound
+	}
+	child.SetLogLevel(logLevel)
+	return nil
+}
+
+// Named create a child logger of default logger.
+// It follows the current default logger, so libraries can create it
+// before application calls New or SetDefault, like Get.
+func Named(name string) *Logger {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	p, ok := children[name]
+	if !ok {
+		p = &Logger{Name: name, placeholder: true}
+		children[name] = p
+	}
+	return p
+}
+
+func Components() map[string]uint8 {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.Components()
+}
+
+func SetComponentLogLevel(name string, logLevel uint8) error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.SetComponentLogLevel(name, logLevel)
+}
@@ -0,0 +1,97 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_named", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	db := l.Named("db")
+	if db != l.Named("db") {
+		t.Error("Named with same name should return same child")
+	}
+	pool := db.Named("pool")
+	if pool.Component() != "db.pool" {
+		t.Error("Nested component is", pool.Component())
+	}
+
+	db.Debug("db debug hidden")
+	pool.Debug("pool debug hidden")
+	db.SetLogLevel(LogLevelDebug)
+	db.Debug("db debug shown")
+	if err = l.SetComponentLogLevel("db.pool", LogLevelDebug); err != nil {
+		t.Error("Set component log level failed.", err)
+	}
+	pool.DebugF("pool %s", "debug shown")
+	if err = l.SetComponentLogLevel("cache", LogLevelDebug); err != ErrComponentNotFound {
+		t.Error("Unknown component should not be found", err)
+	}
+	db.ResetLogLevel()
+	db.Debug("db debug hidden")
+
+	levels := l.Components()
+	if len(levels) != 2 || levels["db"] != LogLevelInfo ||
+		levels["db.pool"] != LogLevelDebug {
+		t.Error("Component levels are", levels)
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	if line := readLine(reader); !strings.Contains(line, "[DEBUG] [db] db debug shown") {
+		t.Error("Child log line is", line)
+	}
+	if line := readLine(reader); !strings.Contains(line, "[DEBUG] [db.pool] pool debug shown") {
+		t.Error("Nested child log line is", line)
+	}
+	if line := readLine(reader); line != "" {
+		t.Error("Unexpected log line", line)
+	}
+}
+
+func TestNamedDefault(t *testing.T) {
+	// Created before default logger is set, like a package variable of library.
+	db := Named("named_default")
+	if db != Named("named_default") {
+		t.Error("Named with same name should return same child")
+	}
+	l, err := NewInternal("./", "zlogger_named_default", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restore := SetDefault(l)
+	defer func() {
+		restore()
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	db.Info("after set default")
+	if db.Component() != "named_default" {
+		t.Error("Component is", db.Component())
+	}
+	if levels := Components(); levels["named_default"] != LogLevelInfo {
+		t.Error("Component levels are", levels)
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if line := readLine(bufio.NewReader(f)); !strings.Contains(line, "[INFO] [named_default] after set default") {
+		t.Error("Child log line is", line)
+	}
+}
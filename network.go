@@ -0,0 +1,383 @@
+package zlogger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultMaxSpoolSize  = 64 << 20
+	// maxPendingSize is the most bytes of entries waiting to be sent in memory.
+	maxPendingSize = 4 << 20
+	// Backoff of reconnection to collector.
+	networkMinBackoff = 100 * time.Millisecond
+	networkMaxBackoff = 30 * time.Second
+	networkTimeout    = 5 * time.Second
+)
+
+var (
+	ErrInvalidNetwork   = errors.New("network should be tcp, udp or http")
+	ErrNetworkSinkFull  = errors.New("too many entries waiting to be sent, entry is dropped")
+	ErrNetworkSinkClose = errors.New("network sink is closed")
+)
+
+// NetworkConfig is the config of network sink.
+type NetworkConfig struct {
+	Network       string        // tcp, udp or http
+	Address       string        // host:port of tcp & udp, URL of http
+	Encoder       Encoder       // Encoder of entries, default is JSONEncoder, one line per entry
+	BatchSize     int           // Most entries sent in a batch, default is defaultBatchSize
+	FlushInterval time.Duration // Interval of sending entries, default is defaultFlushInterval
+	SpoolPath     string        // Spool file of entries not sent, empty is no spool
+	MaxSpoolSize  int64         // Most bytes of spool file, default is defaultMaxSpoolSize
+}
+
+// NetworkSink stream entries to a collector, like Fluent Bit or Vector.
+// Entries are sent in batches by a background goroutine:
+// tcp sends lines, udp sends a datagram per line,
+// http posts newline delimited lines of a batch.
+// If collector is down, entries go to spool file & are replayed in order
+// once it is back. Entries beyond MaxSpoolSize are dropped.
+type NetworkSink struct {
+	config NetworkConfig
+	client *http.Client
+
+	mutex   sync.Mutex // Guard pending & closed
+	pending []byte     // Encoded entries waiting to be sent
+	count   int        // Number of entries in pending
+	closed  bool
+
+	notify  chan struct{}
+	syncCh  chan chan error
+	stop    chan struct{}
+	stopped chan struct{}
+	dropped atomic.Int64
+
+	// Fields below are used by background goroutine only.
+	conn      net.Conn
+	spool     *os.File
+	spoolSize int64
+	backoff   time.Duration
+	retryAt   time.Time
+}
+
+// NewNetworkSink create a network sink, connect to collector lazily.
+// Entries left in spool file by last run are replayed first.
+func NewNetworkSink(config NetworkConfig) (*NetworkSink, error) {
+	switch config.Network {
+	case "tcp", "udp", "http":
+	default:
+		return nil, ErrInvalidNetwork
+	}
+	if config.Encoder == nil {
+		config.Encoder = JSONEncoder{}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	if config.MaxSpoolSize <= 0 {
+		config.MaxSpoolSize = defaultMaxSpoolSize
+	}
+	s := &NetworkSink{
+		config:  config,
+		client:  &http.Client{Timeout: networkTimeout},
+		notify:  make(chan struct{}, 1),
+		syncCh:  make(chan chan error),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if config.SpoolPath != "" {
+		var err error
+		s.spool, err = os.OpenFile(config.SpoolPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, err
+		}
+		info, err := s.spool.Stat()
+		if err != nil {
+			_ = s.spool.Close()
+			return nil, err
+		}
+		s.spoolSize = info.Size()
+	}
+	go s.run()
+	return s, nil
+}
+
+// AddNetworkSink create a network sink & add it to logger.
+// Spool file is default to Logger.Path/Logger.Name.spool.
+func (logger *Logger) AddNetworkSink(config NetworkConfig) (*NetworkSink, error) {
+	logger = logger.resolve().root()
+	if config.SpoolPath == "" {
+		config.SpoolPath = filepath.Join(logger.Path, logger.Name+".spool")
+	}
+	sink, err := NewNetworkSink(config)
+	if err != nil {
+		return nil, err
+	}
+	logger.AddSink(sink)
+	return sink, nil
+}
+
+// Write queue entry to be sent by background goroutine.
+func (s *NetworkSink) Write(e *Entry) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = s.config.Encoder.Encode(*buf, e)
+	if n := len(*buf); n == 0 || (*buf)[n-1] != '\n' {
+		*buf = append(*buf, '\n')
+	}
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return ErrNetworkSinkClose
+	}
+	if len(s.pending)+len(*buf) > maxPendingSize {
+		s.mutex.Unlock()
+		s.dropped.Add(1)
+		return ErrNetworkSinkFull
+	}
+	s.pending = append(s.pending, *buf...)
+	s.count++
+	full := s.count >= s.config.BatchSize
+	s.mutex.Unlock()
+	if full {
+		select {
+		case s.notify <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Dropped get number of entries dropped since spool or memory is full.
+func (s *NetworkSink) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Sync send entries waiting & entries of spool file now, backoff is ignored.
+func (s *NetworkSink) Sync() error {
+	done := make(chan error, 1)
+	select {
+	case s.syncCh <- done:
+		return <-done
+	case <-s.stopped:
+		return nil
+	}
+}
+
+// Close send entries waiting, entries can't be sent are kept in spool file.
+func (s *NetworkSink) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mutex.Unlock()
+	close(s.stop)
+	<-s.stopped
+	return nil
+}
+
+func (s *NetworkSink) run() {
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	defer close(s.stopped)
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.flush(false)
+		case <-s.notify:
+			_ = s.flush(false)
+		case done := <-s.syncCh:
+			done <- s.flush(true)
+		case <-s.stop:
+			_ = s.flush(true)
+			if s.conn != nil {
+				_ = s.conn.Close()
+			}
+			if s.spool != nil {
+				_ = s.spool.Close()
+			}
+			return
+		}
+	}
+}
+
+// flush send spool file then entries waiting, in order.
+// Entries go to spool file if collector is down or in backoff.
+func (s *NetworkSink) flush(force bool) error {
+	s.mutex.Lock()
+	batch, count := s.pending, s.count
+	s.pending, s.count = nil, 0
+	s.mutex.Unlock()
+	if !force && time.Now().Before(s.retryAt) {
+		s.spoolBatch(batch, count)
+		return nil
+	}
+	err := s.replay()
+	if err == nil && len(batch) > 0 {
+		err = s.send(batch)
+	}
+	if err != nil {
+		s.fail()
+		s.spoolBatch(batch, count)
+		return err
+	}
+	s.backoff = 0
+	return nil
+}
+
+// fail close connection & wait a backoff before next try.
+func (s *NetworkSink) fail() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if s.backoff == 0 {
+		s.backoff = networkMinBackoff
+	} else if s.backoff < networkMaxBackoff {
+		s.backoff *= 2
+	}
+	s.retryAt = time.Now().Add(s.backoff)
+}
+
+// spoolBatch append batch to spool file, batch is dropped if spool is full.
+func (s *NetworkSink) spoolBatch(batch []byte, count int) {
+	if len(batch) == 0 {
+		return
+	}
+	if s.spool == nil || s.spoolSize+int64(len(batch)) > s.config.MaxSpoolSize {
+		s.dropped.Add(int64(count))
+		return
+	}
+	n, err := s.spool.Write(batch)
+	s.spoolSize += int64(n)
+	if err != nil {
+		s.dropped.Add(int64(count))
+	}
+}
+
+// replay send entries of spool file in batches.
+// Entries sent are removed from spool file, even if some batch fails.
+func (s *NetworkSink) replay() error {
+	if s.spoolSize == 0 {
+		return nil
+	}
+	reader := bufio.NewReader(io.NewSectionReader(s.spool, 0, s.spoolSize))
+	var offset int64
+	var batch []byte
+	count := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == nil {
+			batch = append(batch, line...)
+			count++
+		}
+		if len(batch) > 0 && (count >= s.config.BatchSize || err != nil) {
+			if sErr := s.send(batch); sErr != nil {
+				s.compactSpool(offset)
+				return sErr
+			}
+			offset += int64(len(batch))
+			batch, count = batch[:0], 0
+		}
+		if err != nil {
+			// Last line without '\n' is broken by a crash, drop it.
+			break
+		}
+	}
+	s.compactSpool(s.spoolSize)
+	return nil
+}
+
+// compactSpool remove first offset bytes of spool file.
+func (s *NetworkSink) compactSpool(offset int64) {
+	if offset == 0 {
+		return
+	}
+	if offset >= s.spoolSize {
+		if err := s.spool.Truncate(0); err == nil {
+			s.spoolSize = 0
+		}
+		return
+	}
+	rest := make([]byte, s.spoolSize-offset)
+	if _, err := s.spool.ReadAt(rest, offset); err != nil {
+		return
+	}
+	tmpPath := s.config.SpoolPath + ".tmp"
+	if err := os.WriteFile(tmpPath, rest, 0666); err != nil {
+		return
+	}
+	if err := os.Rename(tmpPath, s.config.SpoolPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return
+	}
+	spool, err := os.OpenFile(s.config.SpoolPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return
+	}
+	_ = s.spool.Close()
+	s.spool = spool
+	s.spoolSize = int64(len(rest))
+}
+
+// send send batch of lines to collector.
+func (s *NetworkSink) send(batch []byte) error {
+	if s.config.Network == "http" {
+		return s.post(batch)
+	}
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.config.Network, s.config.Address, networkTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(networkTimeout))
+	if s.config.Network == "tcp" {
+		_, err := s.conn.Write(batch)
+		return err
+	}
+	for len(batch) > 0 {
+		line := batch
+		if i := bytes.IndexByte(batch, '\n'); i >= 0 {
+			line = batch[:i]
+			batch = batch[i+1:]
+		} else {
+			batch = nil
+		}
+		if _, err := s.conn.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NetworkSink) post(batch []byte) error {
+	resp, err := s.client.Post(s.config.Address, "application/x-ndjson", bytes.NewReader(batch))
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("collector responds " + resp.Status)
+	}
+	return nil
+}
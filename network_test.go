@@ -0,0 +1,232 @@
+package zlogger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// collector is a http server record lines posted.
+type collector struct {
+	mutex sync.Mutex
+	lines []string
+	down  atomic.Bool
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.down.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	c.mutex.Lock()
+	c.lines = append(c.lines, strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")...)
+	c.mutex.Unlock()
+}
+
+func (c *collector) Lines() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]string(nil), c.lines...)
+}
+
+func TestNetworkSinkHTTP(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	l, err := NewInternal("./", "zlogger_network", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spool := l.Path + l.Name + ".spool"
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+		_ = os.Remove(spool)
+	}()
+	sink, err := l.AddNetworkSink(NetworkConfig{Network: "http", Address: server.URL,
+		Encoder: LogfmtEncoder{}, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.InfoW("first", Int("n", 1))
+	if err = l.Sync(); err != nil {
+		t.Fatal("Sync network sink failed.", err)
+	}
+	// Collector is down, entries go to spool.
+	c.down.Store(true)
+	l.InfoW("second", Int("n", 2))
+	l.InfoW("third", Int("n", 3))
+	if err = sink.Sync(); err == nil {
+		t.Error("Sync to a down collector should fail")
+	}
+	if info, err := os.Stat(spool); err != nil || info.Size() == 0 {
+		t.Error("Entries are not spooled", err)
+	}
+	c.down.Store(false)
+	l.InfoW("fourth", Int("n", 4))
+	if err = sink.Sync(); err != nil {
+		t.Fatal("Replay spool failed.", err)
+	}
+	lines := c.Lines()
+	if len(lines) != 4 {
+		t.Fatal("Collector gets", lines)
+	}
+	for i, want := range []string{"msg=first n=1", "msg=second n=2", "msg=third n=3", "msg=fourth n=4"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Error("Line", i, "is", lines[i])
+		}
+	}
+	if info, err := os.Stat(spool); err != nil || info.Size() != 0 {
+		t.Error("Spool is not cleared", err)
+	}
+}
+
+func TestNetworkSinkSpoolLimit(t *testing.T) {
+	c := &collector{}
+	c.down.Store(true)
+	server := httptest.NewServer(c)
+	defer server.Close()
+	spool := t.TempDir() + "/x.spool"
+	sink, err := NewNetworkSink(NetworkConfig{Network: "http", Address: server.URL,
+		FlushInterval: time.Hour, SpoolPath: spool, MaxSpoolSize: 250})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Entry{Level: LogLevelInfo, Time: time.Now(), Message: strings.Repeat("x", 40)}
+	for i := 0; i < 3; i++ {
+		_ = sink.Write(e)
+		_ = sink.Sync()
+	}
+	if sink.Dropped() != 1 {
+		t.Error("Dropped entries is", sink.Dropped())
+	}
+	_ = sink.Close()
+
+	// Spool is replayed by a new sink after restart.
+	c.down.Store(false)
+	sink, err = NewNetworkSink(NetworkConfig{Network: "http", Address: server.URL,
+		FlushInterval: time.Hour, SpoolPath: spool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+	if err = sink.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if lines := c.Lines(); len(lines) != 2 {
+		t.Error("Collector gets", lines)
+	}
+}
+
+func TestNetworkSinkTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("Can't listen tcp.", err)
+	}
+	defer func() { _ = listener.Close() }()
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	sink, err := NewNetworkSink(NetworkConfig{Network: "tcp", Address: listener.Addr().String(),
+		FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sink.Close() }()
+	_ = sink.Write(&Entry{Level: LogLevelWarn, Time: time.Now(), Message: "over tcp"})
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, `"level":"warn"`) || !strings.Contains(line, `"msg":"over tcp"`) {
+			t.Error("Line is", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Line is not sent")
+	}
+	if _, err = NewNetworkSink(NetworkConfig{Network: "ftp"}); err != ErrInvalidNetwork {
+		t.Error("Invalid network is", err)
+	}
+}
+
+func TestNetworkSinkSyncPolicy(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	l, err := NewInternal("./", "zlogger_network_policy", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		close(release)
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+		_ = os.Remove(l.Path + l.Name + ".spool")
+	}()
+	if _, err = l.AddNetworkSink(NetworkConfig{Network: "http", Address: server.URL,
+		BatchSize: 1, FlushInterval: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if err = l.SetSyncPolicy(SyncPolicyAlways, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Collector hangs, fsync policy should not wait for it.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			l.Error("sync policy", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Fsync policy blocks on network sink")
+	}
+}
+
+func TestNetworkSinkFatal(t *testing.T) {
+	if address := os.Getenv("ZLOGGER_FATAL_COLLECTOR"); address != "" {
+		l, err := NewInternal(os.Getenv("ZLOGGER_FATAL_PATH"), "zlogger_fatal", false, LogLevelAll)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = l.AddNetworkSink(NetworkConfig{Network: "http", Address: address,
+			FlushInterval: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		l.Fatal("fatal entry")
+	}
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestNetworkSinkFatal$")
+	cmd.Env = append(os.Environ(), "ZLOGGER_FATAL_COLLECTOR="+server.URL,
+		"ZLOGGER_FATAL_PATH="+t.TempDir())
+	if err := cmd.Run(); err == nil {
+		t.Fatal("Fatal should exit")
+	}
+	if lines := c.Lines(); len(lines) != 1 || !strings.Contains(lines[0], "fatal entry") {
+		t.Error("Collector lines are", lines)
+	}
+}
@@ -0,0 +1,50 @@
+package zlogger
+
+import (
+	"sync"
+)
+
+// maxPooledBufferSize is the biggest buffer put back to pool.
+// Huge buffer is dropped, or the pool holds too much memory.
+const maxPooledBufferSize = 64 << 10
+
+var (
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			buf := make([]byte, 0, 512)
+			return &buf
+		},
+	}
+	entryPool = sync.Pool{
+		New: func() interface{} {
+			return &Entry{}
+		},
+	}
+)
+
+func getBuffer() *[]byte {
+	buf := bufferPool.Get().(*[]byte)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+func putBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+func getEntry() *Entry {
+	return entryPool.Get().(*Entry)
+}
+
+// putEntry reset e & put it back to pool.
+// Entry must not be used after put back.
+func putEntry(e *Entry) {
+	for i := range e.Fields {
+		e.Fields[i] = Field{}
+	}
+	*e = Entry{Fields: e.Fields[:0]}
+	entryPool.Put(e)
+}
@@ -0,0 +1,181 @@
+package reader
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileTimeLayout is the layout of time suffix of log file name.
+const fileTimeLayout = "2006-01-02_15"
+
+// Filter select entries, zero value select all.
+type Filter struct {
+	Since    time.Time // Entries at or after it, zero is no limit
+	Until    time.Time // Entries before it, zero is no limit
+	MinLevel uint8     // Entries at or above level
+	Caller   string    // Entries which caller contains it
+}
+
+// Match report whether e is selected by filter.
+func (f *Filter) Match(e *Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	return e.Level >= f.MinLevel && strings.Contains(e.Caller, f.Caller)
+}
+
+// logFile is a rotated log file of a logger.
+type logFile struct {
+	path  string
+	start time.Time // Hour in file name, entries of file are after it
+}
+
+// Files get rotated log files of name in path, in chronological order.
+// Files are like name.2006-01-02_15, and name.2006-01-02_15.gz if compressed.
+func Files(path, name string) ([]string, error) {
+	files, err := listFiles(path, name)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+func listFiles(path, name string) ([]logFile, error) {
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []logFile
+	for _, d := range dirEntries {
+		if start, ok := FileTime(d.Name(), name); ok && !d.IsDir() {
+			files = append(files, logFile{path: filepath.Join(path, d.Name()), start: start})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].start.Before(files[j].start)
+	})
+	return files, nil
+}
+
+// FileTime get hour in name of file, which is a log file of logger name.
+func FileTime(file, name string) (time.Time, bool) {
+	if !strings.HasPrefix(file, name+".") {
+		return time.Time{}, false
+	}
+	suffix := strings.TrimSuffix(file[len(name)+1:], ".gz")
+	if len(suffix) != len(fileTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(fileTimeLayout, suffix, time.Local)
+	return t, err == nil
+}
+
+// FileReader read entries of log files one by one, entries not matched are skipped.
+type FileReader struct {
+	Filter Filter
+
+	files  []logFile
+	file   io.ReadCloser
+	reader *Reader
+}
+
+// Open create a reader of all rotated log files of name in path.
+// Files out of time range of filter are not opened.
+func Open(path, name string, filter Filter) (*FileReader, error) {
+	files, err := listFiles(path, name)
+	if err != nil {
+		return nil, err
+	}
+	// Entries of a file are before the next file is created,
+	// which is within the hour after start of next file.
+	for len(files) > 1 && !filter.Since.IsZero() &&
+		!files[1].start.Add(time.Hour).After(filter.Since) {
+		files = files[1:]
+	}
+	for i := range files {
+		if !filter.Until.IsZero() && !files[i].start.Before(filter.Until) {
+			files = files[:i]
+			break
+		}
+	}
+	return &FileReader{Filter: filter, files: files}, nil
+}
+
+// OpenFiles create a reader of files in order, file ends with .gz is decompressed.
+func OpenFiles(filter Filter, paths ...string) *FileReader {
+	files := make([]logFile, len(paths))
+	for i, path := range paths {
+		files[i].path = path
+	}
+	return &FileReader{Filter: filter, files: files}
+}
+
+// Next get the next entry matched by filter, return io.EOF after the last file.
+func (r *FileReader) Next() (*Entry, error) {
+	for {
+		if r.reader == nil {
+			if len(r.files) == 0 {
+				return nil, io.EOF
+			}
+			if err := r.open(r.files[0].path); err != nil {
+				return nil, err
+			}
+			r.files = r.files[1:]
+		}
+		e, err := r.reader.Next()
+		if err == io.EOF {
+			_ = r.file.Close()
+			r.file, r.reader = nil, nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.Filter.Match(e) {
+			return e, nil
+		}
+	}
+}
+
+func (r *FileReader) open(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		reader = gz
+	}
+	r.reader = NewReader(reader)
+	r.reader.source = path
+	return nil
+}
+
+// Close close the file being read.
+func (r *FileReader) Close() error {
+	r.files = nil
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file, r.reader = nil, nil
+	return err
+}
@@ -0,0 +1,307 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFollowInterval = time.Second
+	// followChunkSize is the bytes read from file at a time.
+	followChunkSize = 1 << 20
+)
+
+var (
+	ErrFollowerStarted = errors.New("follower is started")
+)
+
+// Position is the position of a follower in rotated files of a log.
+type Position struct {
+	File   string `json:"file"`   // Base name of log file, like name.2006-01-02_15
+	Offset int64  `json:"offset"` // Offset after the last entry read
+}
+
+// Follower read entries of a log as files grow, across rotations.
+// When logger switches to a new hour file, entries of the old file
+// are drained first, then entries of the new file follow.
+// Entries are expected to be written in one write, like zlogger does,
+// so the end of data is taken as the end of the last entry.
+type Follower struct {
+	Interval   time.Duration // Interval of checking new entries, default is defaultFollowInterval
+	Checkpoint string        // File to save position, empty is not saved
+
+	path, name string
+	mutex      sync.Mutex // Protect state below
+	started    bool
+	pos        Position // Position after the last entry returned
+	saved      Position // Position saved to checkpoint
+	file       *os.File
+	start      time.Time // Hour of current file
+	readOffset int64     // Offset of data read from current file
+	queue      []*Entry  // Entries read but not returned
+	nextFile   string    // Newer file seen, switch to it if current file doesn't grow
+	closed     chan struct{}
+	closeOnce  sync.Once
+}
+
+// Follow create a follower of rotated files of name in path.
+// It starts from position in Checkpoint if the file exists, then position
+// set by Seek, otherwise the beginning of the newest file.
+func Follow(path, name string) *Follower {
+	return &Follower{path: path, name: name, closed: make(chan struct{})}
+}
+
+// Seek set start position, it must be called before Next.
+func (f *Follower) Seek(pos Position) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.started {
+		return ErrFollowerStarted
+	}
+	f.pos = pos
+	return nil
+}
+
+// Next get the next entry, wait until there is one.
+// Position of entry returned last time is saved to checkpoint, as it is handled.
+// Return io.EOF after Close.
+func (f *Follower) Next() (*Entry, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.started {
+		if err := f.begin(); err != nil {
+			return nil, err
+		}
+		f.started = true
+	} else if err := f.save(); err != nil {
+		return nil, err
+	}
+	interval := f.Interval
+	if interval <= 0 {
+		interval = defaultFollowInterval
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for len(f.queue) == 0 {
+		select {
+		case <-f.closed:
+			return nil, io.EOF
+		default:
+		}
+		grown, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+		if grown {
+			f.nextFile = ""
+			continue
+		}
+		switched, err := f.rotate()
+		if err != nil {
+			return nil, err
+		}
+		if switched {
+			continue
+		}
+		f.mutex.Unlock()
+		timer.Reset(interval)
+		select {
+		case <-f.closed:
+		case <-timer.C:
+		}
+		f.mutex.Lock()
+	}
+	e := f.queue[0]
+	f.queue = f.queue[1:]
+	f.pos.Offset = e.end
+	return e, nil
+}
+
+// Position get position after the last entry returned.
+func (f *Follower) Position() Position {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.pos
+}
+
+// Close stop follower & save position to checkpoint.
+func (f *Follower) Close() error {
+	f.closeOnce.Do(func() { close(f.closed) })
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.started {
+		return nil
+	}
+	err := f.save()
+	if f.file != nil {
+		_ = f.file.Close()
+		f.file = nil
+	}
+	return err
+}
+
+// begin open file of start position.
+func (f *Follower) begin() error {
+	if f.Checkpoint != "" {
+		data, err := os.ReadFile(f.Checkpoint)
+		if err == nil {
+			err = json.Unmarshal(data, &f.pos)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	f.saved = f.pos
+	if f.pos.File == "" {
+		files, err := listFiles(f.path, f.name)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			// Wait for the first file.
+			return nil
+		}
+		f.pos = Position{File: filepath.Base(files[len(files)-1].path)}
+	}
+	return f.open(f.pos)
+}
+
+// open open file of pos, file removed is skipped by rotate later.
+func (f *Follower) open(pos Position) error {
+	f.start, _ = FileTime(pos.File, f.name)
+	file, err := os.Open(filepath.Join(f.path, pos.File))
+	if os.IsNotExist(err) {
+		f.pos, f.readOffset = Position{File: pos.File}, 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	if pos.Offset > info.Size() {
+		// File is replaced, read it from the beginning.
+		pos.Offset = 0
+	}
+	f.file, f.pos, f.readOffset = file, pos, pos.Offset
+	return nil
+}
+
+// read read entries appended to current file to queue, report whether file grows.
+func (f *Follower) read() (bool, error) {
+	if f.file == nil {
+		return false, nil
+	}
+	info, err := f.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	remain := info.Size() - f.readOffset
+	if remain <= 0 {
+		return false, nil
+	}
+	size := int64(followChunkSize)
+	for {
+		if size > remain {
+			size = remain
+		}
+		data := make([]byte, size)
+		if _, err = f.file.ReadAt(data, f.readOffset); err != nil && err != io.EOF {
+			return false, err
+		}
+		end := bytes.LastIndexByte(data, '\n')
+		if end < 0 {
+			if size == remain {
+				// The last line is being written.
+				return false, nil
+			}
+			size *= 2
+			continue
+		}
+		r := NewReader(bytes.NewReader(data[:end+1]))
+		r.source = f.file.Name()
+		r.offset = f.readOffset
+		var entries []*Entry
+		for {
+			e, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return false, err
+			}
+			entries = append(entries, e)
+		}
+		if size < remain && len(entries) > 1 {
+			// The last entry may go on in data not read, read it next time.
+			entries = entries[:len(entries)-1]
+		}
+		f.queue = append(f.queue, entries...)
+		f.readOffset = entries[len(entries)-1].end
+		return true, nil
+	}
+}
+
+// rotate switch to the file after current one, report whether it is switched.
+// Current file is left only if a newer file is seen twice & current file
+// doesn't grow between, so entries written before rotation are not lost.
+func (f *Follower) rotate() (bool, error) {
+	files, err := listFiles(f.path, f.name)
+	if err != nil {
+		return false, err
+	}
+	var next *logFile
+	for i := range files {
+		// Compressed file is not written any more, it is not followed.
+		if strings.HasSuffix(files[i].path, ".gz") {
+			continue
+		}
+		if f.pos.File == "" || files[i].start.After(f.start) {
+			next = &files[i]
+			break
+		}
+	}
+	if next == nil {
+		return false, nil
+	}
+	if f.file != nil && f.nextFile != next.path {
+		f.nextFile = next.path
+		return false, nil
+	}
+	if f.file != nil {
+		_ = f.file.Close()
+		f.file = nil
+	}
+	f.nextFile = ""
+	return true, f.open(Position{File: filepath.Base(next.path)})
+}
+
+// save save position of entries handled to checkpoint.
+func (f *Follower) save() error {
+	if f.Checkpoint == "" || f.pos == f.saved {
+		return nil
+	}
+	data, err := json.Marshal(f.pos)
+	if err != nil {
+		return err
+	}
+	tmp := f.Checkpoint + ".tmp"
+	if err = os.WriteFile(tmp, data, 0666); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, f.Checkpoint); err != nil {
+		return err
+	}
+	f.saved = f.pos
+	return nil
+}
@@ -0,0 +1,104 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendLog(t *testing.T, path string, lines ...string) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	for _, line := range lines {
+		if _, err = file.WriteString(line + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// nextMessage get message of the next entry of follower, fail after timeout.
+func nextMessage(t *testing.T, f *Follower) string {
+	t.Helper()
+	type result struct {
+		e   *Entry
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		e, err := f.Next()
+		ch <- result{e, err}
+	}()
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			t.Fatal("Follow failed.", r.err)
+		}
+		return r.e.Message
+	case <-time.After(2 * time.Second):
+		t.Fatal("No entry is followed")
+	}
+	return ""
+}
+
+func TestFollow(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "app.2024-01-01_10")
+	second := filepath.Join(dir, "app.2024-01-01_11")
+	checkpoint := filepath.Join(dir, "app.checkpoint")
+	appendLog(t, first,
+		"2024/01/01 10:00:00.000000 a.go:1: [INFO] one",
+		"2024/01/01 10:00:01.000000 a.go:1: [ERROR] two",
+		"\tframe")
+
+	f := Follow(dir, "app")
+	f.Interval = 5 * time.Millisecond
+	f.Checkpoint = checkpoint
+	if msg := nextMessage(t, f); msg != "one" {
+		t.Error("First entry is", msg)
+	}
+	if msg := nextMessage(t, f); msg != "two" {
+		t.Error("Second entry is", msg)
+	}
+	// Position after "two" is saved, it is not read again after restart.
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(first)
+	if pos := f.Position(); pos.File != "app.2024-01-01_10" || pos.Offset != info.Size() {
+		t.Error("Position is", pos)
+	}
+
+	appendLog(t, first, "2024/01/01 10:59:59.000000 a.go:1: [INFO] three")
+	f = Follow(dir, "app")
+	f.Interval = 5 * time.Millisecond
+	f.Checkpoint = checkpoint
+	defer func() { _ = f.Close() }()
+	if msg := nextMessage(t, f); msg != "three" {
+		t.Error("Entry after restart is", msg)
+	}
+	// Rotate, entry written to old file just after rotation is not lost.
+	appendLog(t, second, "2024/01/01 11:00:00.000000 a.go:1: [INFO] five")
+	appendLog(t, first, "2024/01/01 11:00:00.000000 a.go:1: [INFO] four")
+	if msg := nextMessage(t, f); msg != "four" {
+		t.Error("Entry of old file is", msg)
+	}
+	if msg := nextMessage(t, f); msg != "five" {
+		t.Error("Entry of new file is", msg)
+	}
+	// Partial line is not read until it is finished.
+	appendLog(t, second, "2024/01/01 11:00:01.000000 a.go:1: [INFO] six")
+	file, _ := os.OpenFile(second, os.O_WRONLY|os.O_APPEND, 0666)
+	_, _ = file.WriteString("2024/01/01 11:00:02.000000 a.go:1: [INFO] sev")
+	if msg := nextMessage(t, f); msg != "six" {
+		t.Error("Entry before partial line is", msg)
+	}
+	_, _ = file.WriteString("en\n")
+	_ = file.Close()
+	if msg := nextMessage(t, f); msg != "seven" {
+		t.Error("Finished line is", msg)
+	}
+}
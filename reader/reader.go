@@ -0,0 +1,224 @@
+// Package reader parse log files written by zlogger.
+// Both text format of TextEncoder & JSON format of JSONEncoder are supported.
+package reader
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/zhangyu0310/zlogger"
+)
+
+// Layout of time of text & JSON format.
+const (
+	textTimeLayout = "2006/01/02 15:04:05.000000"
+	jsonTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// Entry is a log entry parsed from log file.
+type Entry struct {
+	Time      time.Time              // Time of entry, zero for lines not in log format
+	Level     uint8                  // Level of entry, like zlogger.LogLevelInfo
+	Caller    string                 // File & line of call point, like file.go:12
+	Function  string                 // Function of call point
+	Component string                 // Component name of child logger
+	Message   string                 // Message, with errors & fields for text format
+	Stack     []string               // Stacktrace, one frame per item
+	Fields    map[string]interface{} // Errors & fields of JSON format
+	Raw       string                 // Lines of entry as read, without the last newline
+	Source    string                 // File entry read from
+
+	end int64 // Offset after the entry in source
+}
+
+// Reader parse entries from a stream of log lines.
+// Lines of text format not starting with time, like raw multi-line message,
+// are appended to message of the entry before them.
+// Lines starting with tab are stacktrace, or continuation with "\t| " marker.
+type Reader struct {
+	reader  *bufio.Reader
+	source  string
+	offset  int64  // Offset after lines read
+	pending *Entry // Entry read ahead
+}
+
+// NewReader create a reader parse entries from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReaderSize(r, 64<<10)}
+}
+
+// readLine read a line without newline, return io.EOF at end.
+func (r *Reader) readLine() (string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	r.offset += int64(len(line))
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// Next get the next entry, return io.EOF at end.
+func (r *Reader) Next() (*Entry, error) {
+	e := r.pending
+	r.pending = nil
+	if e == nil {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if e = ParseLine(line); e == nil {
+			e = &Entry{Message: line, Raw: line}
+		}
+		e.Source = r.source
+	}
+	for {
+		start := r.offset
+		line, err := r.readLine()
+		if err == io.EOF {
+			e.end = r.offset
+			return e, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if next := ParseLine(line); next != nil {
+			next.Source = r.source
+			r.pending = next
+			e.end = start
+			return e, nil
+		}
+		e.Raw += "\n" + line
+		switch {
+		case strings.HasPrefix(line, "\t| "):
+			e.Message += "\n" + line[len("\t| "):]
+		case strings.HasPrefix(line, "\t"):
+			e.Stack = append(e.Stack, line[1:])
+		default:
+			e.Message += "\n" + line
+		}
+	}
+}
+
+// ParseLine parse the first line of an entry, nil if line is not in log format.
+func ParseLine(line string) *Entry {
+	if strings.HasPrefix(line, "{") {
+		if e := parseJSON(line); e != nil {
+			return e
+		}
+	}
+	return parseText(line)
+}
+
+// levelTags is level tag of text format.
+var levelTags = []string{"[DEBUG]", "[INFO]", "[WARN]", "[ERROR]", "[FATAL]", "[PANIC]", "[ALL]", "[OFF]"}
+
+// parseText parse a line like:
+// 2006/01/02 15:04:05.000000 file.go:12:func: [INFO] [component] message key=value
+// Caller, function & component are optional,
+// so message starts with a word in brackets is taken as component.
+func parseText(line string) *Entry {
+	if len(line) < len(textTimeLayout)+1 || line[len(textTimeLayout)] != ' ' {
+		return nil
+	}
+	t, err := time.ParseInLocation(textTimeLayout, line[:len(textTimeLayout)], time.Local)
+	if err != nil {
+		return nil
+	}
+	e := &Entry{Time: t, Raw: line}
+	rest := line[len(textTimeLayout)+1:]
+	// Find the earliest level tag, after caller if there is.
+	start, tag := -1, ""
+	for _, lt := range levelTags {
+		i := 0
+		if !strings.HasPrefix(rest, lt) {
+			i = strings.Index(rest, ": "+lt)
+			if i < 0 {
+				continue
+			}
+			i += len(": ")
+		}
+		if start < 0 || i < start {
+			start, tag = i, lt
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	if start > 0 {
+		e.Caller, e.Function = splitCaller(rest[:start-len(": ")])
+	}
+	e.Level, _ = zlogger.Str2LogLevel(tag[1 : len(tag)-1])
+	rest = rest[start+len(tag):]
+	if strings.HasPrefix(rest, " [") {
+		if i := strings.Index(rest, "] "); i > 0 && !strings.ContainsAny(rest[2:i], " []") {
+			e.Component = rest[2:i]
+			rest = rest[i+1:]
+		}
+	}
+	e.Message = strings.TrimPrefix(rest, " ")
+	return e
+}
+
+// splitCaller split "file.go:12:func" to caller & function.
+func splitCaller(s string) (caller, function string) {
+	// Line is the first ":digits" followed by end or ':'.
+	for i := 0; i < len(s); i++ {
+		if s[i] != ':' {
+			continue
+		}
+		j := i + 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j > i+1 && (j == len(s) || s[j] == ':') {
+			if j < len(s) {
+				return s[:j], s[j+1:]
+			}
+			return s, ""
+		}
+	}
+	return s, ""
+}
+
+// parseJSON parse a line of JSONEncoder, nil if it is not a log entry.
+func parseJSON(line string) *Entry {
+	var m map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&m); err != nil {
+		return nil
+	}
+	ts, _ := m["time"].(string)
+	t, err := time.Parse(jsonTimeLayout, ts)
+	if err != nil {
+		return nil
+	}
+	e := &Entry{Time: t, Raw: line}
+	level, _ := m["level"].(string)
+	e.Level, _ = zlogger.Str2LogLevel(level)
+	e.Caller, _ = m["caller"].(string)
+	e.Function, _ = m["func"].(string)
+	e.Component, _ = m["logger"].(string)
+	e.Message, _ = m["msg"].(string)
+	if stack, ok := m["stacktrace"].([]interface{}); ok {
+		for _, frame := range stack {
+			if s, ok := frame.(string); ok {
+				e.Stack = append(e.Stack, s)
+			}
+		}
+	}
+	for _, key := range []string{"time", "level", "caller", "func", "logger", "msg", "stacktrace"} {
+		delete(m, key)
+	}
+	if len(m) > 0 {
+		e.Fields = m
+	}
+	return e
+}
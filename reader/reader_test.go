@@ -0,0 +1,162 @@
+package reader
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zhangyu0310/zlogger"
+)
+
+func readAll(t *testing.T, next func() (*Entry, error)) []*Entry {
+	var entries []*Entry
+	for {
+		e, err := next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal("Read entry failed.", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func TestReader(t *testing.T) {
+	dir := t.TempDir()
+	l, err := zlogger.NewInternal(dir, "app", false, zlogger.LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("plain message")
+	l.Named("db").Warn("[not component]", errors.New("lost"))
+	l.Debug("raw\nmultiline")
+	l.SetCallerFunction(true)
+	l.SetStacktraceLevel(zlogger.LogLevelError)
+	l.SetEncoder(zlogger.TextEncoder{Multiline: zlogger.MultilineIndent})
+	l.ErrorW("indent\nmultiline", zlogger.Int("n", 1))
+	l.SetStacktraceLevel(zlogger.LogLevelOff)
+	l.SetEncoder(zlogger.JSONEncoder{})
+	l.Named("db").InfoW("json", zlogger.String("key", "value"))
+	l.Close()
+
+	file, err := os.Open(filepath.Join(dir, l.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	entries := readAll(t, NewReader(file).Next)
+	if len(entries) != 5 {
+		t.Fatal("Entries are", len(entries))
+	}
+	want := []Entry{
+		{Level: zlogger.LogLevelInfo, Message: "plain message"},
+		{Level: zlogger.LogLevelWarn, Component: "db", Message: "[not component] lost"},
+		{Level: zlogger.LogLevelDebug, Message: "raw\nmultiline"},
+		{Level: zlogger.LogLevelError, Function: "reader.TestReader", Message: "indent\nmultiline n=1"},
+		{Level: zlogger.LogLevelInfo, Component: "db", Function: "reader.TestReader", Message: "json"},
+	}
+	for i, e := range entries {
+		if e.Level != want[i].Level || e.Component != want[i].Component ||
+			e.Function != want[i].Function || e.Message != want[i].Message {
+			t.Errorf("Entry %d is %+v", i, e)
+		}
+		if !strings.HasPrefix(e.Caller, "reader_test.go:") || time.Since(e.Time) > time.Minute {
+			t.Errorf("Caller or time of entry %d is wrong: %s %s", i, e.Caller, e.Time)
+		}
+	}
+	if len(entries[3].Stack) == 0 || !strings.Contains(entries[3].Stack[0], "TestReader") {
+		t.Error("Stack is", entries[3].Stack)
+	}
+	if entries[4].Fields["key"] != "value" {
+		t.Error("Fields are", entries[4].Fields)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	e := ParseLine("2024/01/02 03:04:05.000006 C:/src/a.go:12:main.main: [INFO] [api] msg: [ERROR] x")
+	if e == nil || e.Caller != "C:/src/a.go:12" || e.Function != "main.main" ||
+		e.Level != zlogger.LogLevelInfo || e.Component != "api" || e.Message != "msg: [ERROR] x" ||
+		e.Time.Nanosecond() != 6000 {
+		t.Errorf("Entry is %+v", e)
+	}
+	if e = ParseLine("2024/01/02 03:04:05.000006 [PANIC] "); e == nil || e.Level != zlogger.LogLevelPanic || e.Caller != "" {
+		t.Errorf("Entry is %+v", e)
+	}
+	for _, line := range []string{"", "panic: oops", "2024/01/02 03:04:05.000006 no level", `{"msg":"no time"}`} {
+		if e = ParseLine(line); e != nil {
+			t.Errorf("Line %q is parsed as %+v", line, e)
+		}
+	}
+}
+
+func writeLogFile(t *testing.T, path string, lines ...string) {
+	var w io.Writer
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	w = file
+	if strings.HasSuffix(path, ".gz") {
+		gz := gzip.NewWriter(file)
+		defer func() { _ = gz.Close() }()
+		w = gz
+	}
+	if _, err = io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	writeLogFile(t, filepath.Join(dir, "app.2024-01-01_10"),
+		"2024/01/01 10:00:00.000000 a.go:1: [INFO] ten",
+		"2024/01/01 10:30:00.000000 b.go:2: [ERROR] ten thirty")
+	writeLogFile(t, filepath.Join(dir, "app.2024-01-01_09.gz"),
+		"2024/01/01 09:00:00.000000 a.go:1: [ERROR] nine",
+		"2024/01/01 10:05:00.000000 a.go:1: [WARN] nine late")
+	writeLogFile(t, filepath.Join(dir, "app.2024-01-01_08"),
+		"2024/01/01 08:00:00.000000 a.go:1: [ERROR] eight")
+	writeLogFile(t, filepath.Join(dir, "app.2024-01-01_12"),
+		"2024/01/01 12:00:00.000000 a.go:1: [ERROR] twelve")
+	writeLogFile(t, filepath.Join(dir, "app.spool"), `{"msg":"x"}`)
+	writeLogFile(t, filepath.Join(dir, "api.2024-01-01_10"),
+		"2024/01/01 10:00:00.000000 a.go:1: [ERROR] other")
+
+	files, err := Files(dir, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	if strings.Join(names, " ") != "app.2024-01-01_08 app.2024-01-01_09.gz app.2024-01-01_10 app.2024-01-01_12" {
+		t.Error("Files are", names)
+	}
+
+	filter := Filter{
+		Since:    time.Date(2024, 1, 1, 9, 0, 0, 0, time.Local),
+		Until:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local),
+		MinLevel: zlogger.LogLevelWarn,
+		Caller:   "a.go",
+	}
+	r, err := Open(dir, "app", filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	var messages []string
+	for _, e := range readAll(t, r.Next) {
+		messages = append(messages, e.Message)
+	}
+	if strings.Join(messages, ",") != "nine,nine late" {
+		t.Error("Messages are", messages)
+	}
+}
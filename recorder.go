@@ -0,0 +1,161 @@
+package zlogger
+
+import (
+	"sync"
+	"time"
+)
+
+// Messages of marker entries around dumped entries.
+const (
+	recorderBeginMessage = "flight recorder dump begin"
+	recorderEndMessage   = "flight recorder dump end"
+)
+
+// flightRecorder keep recent encoded entries in a ring.
+type flightRecorder struct {
+	mutex      sync.Mutex
+	maxEntries int            // Most entries to keep, 0 is no limit
+	maxBytes   int            // Most bytes to keep, 0 is no limit
+	records    []flightRecord // Ring of entries
+	head       int            // Index of the oldest record
+	count      int            // Number of records
+	size       int            // Bytes of records
+}
+
+// flightRecord is an entry in ring of flight recorder.
+// Entry written to log file keep its size only, it is not dumped again.
+type flightRecord struct {
+	data    []byte
+	size    int
+	written bool
+}
+
+// SetFlightRecorder keep the last maxEntries entries or maxBytes bytes in memory,
+// entries below log level are built for it too.
+// 0 is no limit, both 0 turns recorder off.
+// When an entry at or above LogLevelError is written, or DumpRecent is called,
+// entries kept but not written to log file are written as a marked block,
+// in the order they are logged.
+// Hooks don't run on entries below log level, they are kept as logged.
+// Child loggers record to flight recorder of root.
+func (logger *Logger) SetFlightRecorder(maxEntries, maxBytes int) {
+	root := logger.resolve().root()
+	if maxEntries <= 0 && maxBytes <= 0 {
+		root.recorder.Store(nil)
+		return
+	}
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	root.recorder.Store(&flightRecorder{maxEntries: maxEntries, maxBytes: maxBytes})
+}
+
+// DumpRecent write entries kept by flight recorder to log file.
+func (logger *Logger) DumpRecent() {
+	root := logger.resolve().root()
+	r := root.recorder.Load()
+	if r == nil {
+		return
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = r.dump(*buf, root.GetEncoder())
+	if len(*buf) == 0 {
+		return
+	}
+	root.writeFile(*buf)
+}
+
+// disabled report whether entry at level is dropped without building it.
+// Entry below log level is still built if flight recorder is on.
+func (logger *Logger) disabled(level uint8) bool {
+	return logger.GetLogLevel() > level && logger.root().recorder.Load() == nil
+}
+
+// record encode entry below log level into flight recorder of logger.
+func (logger *Logger) record(e *Entry) {
+	r := logger.recorder.Load()
+	if r == nil {
+		return
+	}
+	buf := getBuffer()
+	*buf = logger.GetEncoder().Encode(*buf, e)
+	r.add(*buf, false)
+	putBuffer(buf)
+}
+
+// add copy record into ring, the oldest records are dropped if it is full.
+// Only size of record written to log file is kept.
+func (r *flightRecorder) add(record []byte, written bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.maxBytes > 0 && len(record) > r.maxBytes {
+		return
+	}
+	for r.count > 0 && ((r.maxEntries > 0 && r.count >= r.maxEntries) ||
+		(r.maxBytes > 0 && r.size+len(record) > r.maxBytes)) {
+		r.size -= r.records[r.head].size
+		r.head = (r.head + 1) % len(r.records)
+		r.count--
+	}
+	if r.count == len(r.records) {
+		// Grow ring, records are moved to start from 0.
+		records := make([]flightRecord, 0, 2*len(r.records)+8)
+		for i := 0; i < r.count; i++ {
+			records = append(records, r.records[(r.head+i)%len(r.records)])
+		}
+		r.records = records[:cap(records)]
+		r.head = 0
+	}
+	rec := &r.records[(r.head+r.count)%len(r.records)]
+	// Reuse memory of dropped record.
+	rec.data = rec.data[:0]
+	if !written {
+		rec.data = append(rec.data, record...)
+	}
+	rec.size, rec.written = len(record), written
+	r.count++
+	r.size += len(record)
+}
+
+// dump append records not written between marker entries to buf & clear the ring.
+// Nothing is appended if all records are written.
+func (r *flightRecorder) dump(buf []byte, enc Encoder) []byte {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	n := 0
+	for i := 0; i < r.count; i++ {
+		if !r.records[(r.head+i)%len(r.records)].written {
+			n++
+		}
+	}
+	if n > 0 {
+		marker := Entry{Level: LogLevelInfo, Time: time.Now(), Message: recorderBeginMessage,
+			Fields: []Field{Int("entries", n)}}
+		buf = enc.Encode(buf, &marker)
+		for i := 0; i < r.count; i++ {
+			if rec := &r.records[(r.head+i)%len(r.records)]; !rec.written {
+				buf = append(buf, rec.data...)
+			}
+		}
+		marker.Message, marker.Fields = recorderEndMessage, nil
+		buf = enc.Encode(buf, &marker)
+	}
+	r.head, r.count, r.size = 0, 0, 0
+	return buf
+}
+
+func SetFlightRecorder(maxEntries, maxBytes int) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetFlightRecorder(maxEntries, maxBytes)
+}
+
+func DumpRecent() {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.DumpRecent()
+}
@@ -0,0 +1,88 @@
+package zlogger
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_recorder", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	// Hooks don't run on entries kept by flight recorder only.
+	fired := 0
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		fired++
+		if e.Message == "health" {
+			return false
+		}
+		e.Fields = append(e.Fields, String("host", "h1"))
+		return true
+	}))
+	l.SetFlightRecorder(3, 0)
+	l.Debug("d1")
+	l.Debug("d2")
+	l.Info("i1")
+	l.Info("health")
+	l.Debug("d3")
+	l.Named("db").DebugW("d4", Int("n", 4))
+	l.Error("e1")
+	l.Debug("d5")
+	l.DumpRecent()
+	l.DumpRecent()
+	l.SetFlightRecorder(0, 10)
+	l.Debug("off")
+	l.Error("e2")
+
+	data, err := os.ReadFile(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	// The last 3 entries before e1 are i1, d3 & d4, i1 is written already.
+	want := []string{
+		"[INFO] i1 host=h1",
+		"[INFO] flight recorder dump begin entries=2",
+		"[DEBUG] d3",
+		"[DEBUG] [db] d4 n=4",
+		"[INFO] flight recorder dump end",
+		"[ERROR] e1 host=h1",
+		"[INFO] flight recorder dump begin entries=1",
+		"[DEBUG] d5",
+		"[INFO] flight recorder dump end",
+		"[ERROR] e2 host=h1",
+	}
+	if len(lines) != len(want) {
+		t.Fatal("Log file is", lines)
+	}
+	for i := range want {
+		if !strings.HasSuffix(lines[i], want[i]) {
+			t.Errorf("Line %d is %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if fired != 4 {
+		t.Error("Hooks fired", fired)
+	}
+}
+
+func TestFlightRecorderBytes(t *testing.T) {
+	r := &flightRecorder{maxBytes: 10}
+	for _, record := range []string{"aaaa", "bbbb", "cccc", "too large record", "dd"} {
+		r.add([]byte(record), false)
+	}
+	// Written records count in bytes kept, but they are not dumped.
+	r.add([]byte("ee"), true)
+	if got := string(r.dump(nil, LogfmtEncoder{})); !strings.Contains(got, "entries=2") ||
+		!strings.Contains(got, "ccccdd") || strings.Contains(got, "bbbb") || strings.Contains(got, "ee") {
+		t.Error("Dump is", got)
+	}
+	if got := r.dump(nil, LogfmtEncoder{}); len(got) != 0 {
+		t.Error("Ring is not cleared", string(got))
+	}
+}
@@ -0,0 +1,253 @@
+package zlogger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replace value of masked fields.
+const RedactedValue = "[REDACTED]"
+
+// Redactor can be implemented by value to supply its log-safe form.
+// It is used for message arguments, fields & errors,
+// whether redaction rules are configured or not.
+type Redactor interface {
+	Redact() string
+}
+
+// redactRule replace matches of pattern by replacement.
+type redactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// redaction is the rules of logger, replaced as a whole when changed.
+type redaction struct {
+	rules  []redactRule
+	fields map[string]bool // Masked field names in lower case
+}
+
+// redactedError is an error of which detail is redacted.
+// It wraps the original error, so errors.Is & errors.As still work.
+type redactedError struct {
+	err  error
+	info *ErrorInfo // Redacted detail of err
+}
+
+func (e *redactedError) Error() string {
+	return e.info.Message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// AddRedactPattern replace matches of pattern in message, string fields & errors
+// by replacement, replacement can use $1 like regexp.ReplaceAllString.
+// Child loggers follow rules of root.
+func (logger *Logger) AddRedactPattern(pattern, replacement string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	logger.updateRedaction(func(r *redaction) {
+		r.rules = append(r.rules, redactRule{pattern: re, replacement: replacement})
+	})
+	return nil
+}
+
+// AddRedactFields mask value of fields by name, like password & authorization.
+// Name is case-insensitive.
+func (logger *Logger) AddRedactFields(names ...string) {
+	logger.updateRedaction(func(r *redaction) {
+		for _, name := range names {
+			r.fields[strings.ToLower(name)] = true
+		}
+	})
+}
+
+// updateRedaction copy rules of logger, update & store them.
+func (logger *Logger) updateRedaction(update func(r *redaction)) {
+	root := logger.resolve().root()
+	root.redactionMutex.Lock()
+	defer root.redactionMutex.Unlock()
+	r := &redaction{fields: make(map[string]bool)}
+	if old := root.redaction.Load(); old != nil {
+		r.rules = append(r.rules, old.rules...)
+		for name := range old.fields {
+			r.fields[name] = true
+		}
+	}
+	update(r)
+	root.redaction.Store(r)
+}
+
+// redact apply redaction to entry before it is written to any sink.
+// Only values implement Redactor are checked if there is no rule.
+func (logger *Logger) redact(e *Entry) {
+	r := logger.redaction.Load()
+	if r != nil && len(r.rules) > 0 {
+		r.redactMessage(e)
+	}
+	for i, err := range e.Errors {
+		e.Errors[i] = r.redactError(err)
+	}
+	for i := range e.Fields {
+		r.redactField(&e.Fields[i])
+	}
+}
+
+// apply replace matches of rules in s.
+func (r *redaction) apply(s string) string {
+	for _, rule := range r.rules {
+		s = rule.pattern.ReplaceAllString(s, rule.replacement)
+	}
+	return s
+}
+
+// redactMessage apply rules to message of e.
+// Parts between errors are redacted apart, so offsets of errors are kept right.
+func (r *redaction) redactMessage(e *Entry) {
+	if len(e.ErrorOffsets) == 0 {
+		e.Message = r.apply(e.Message)
+		return
+	}
+	var b strings.Builder
+	pos := 0
+	for i, offset := range e.ErrorOffsets {
+		if offset < pos {
+			offset = pos
+		} else if offset > len(e.Message) {
+			offset = len(e.Message)
+		}
+		b.WriteString(r.apply(e.Message[pos:offset]))
+		e.ErrorOffsets[i] = b.Len()
+		pos = offset
+	}
+	b.WriteString(r.apply(e.Message[pos:]))
+	e.Message = b.String()
+}
+
+// redactError redact messages & fields of err and its wrapped chain.
+// err is returned as is if nothing is redacted.
+func (r *redaction) redactError(err error) error {
+	if red, ok := err.(Redactor); ok {
+		info := &ErrorInfo{Message: red.Redact(), Type: fmt.Sprintf("%T", err)}
+		return &redactedError{err: err, info: info}
+	}
+	if r == nil || (len(r.rules) == 0 && len(r.fields) == 0) {
+		return err
+	}
+	info := NewErrorInfo(err)
+	if !r.redactErrorInfo(info) {
+		return err
+	}
+	return &redactedError{err: err, info: info}
+}
+
+// redactErrorInfo apply rules to messages & fields of info and its causes,
+// report whether anything is redacted.
+func (r *redaction) redactErrorInfo(info *ErrorInfo) bool {
+	redacted := false
+	if len(r.rules) > 0 {
+		if msg := r.apply(info.Message); msg != info.Message {
+			info.Message = msg
+			redacted = true
+		}
+	}
+	// Fields may be owned by error, copy them before change.
+	var fields map[string]interface{}
+	for k, v := range info.Fields {
+		var value interface{}
+		if r.fields[strings.ToLower(k)] {
+			value = RedactedValue
+		} else if s, ok := v.(string); ok && len(r.rules) > 0 {
+			if s2 := r.apply(s); s2 != s {
+				value = s2
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{}, len(info.Fields))
+			for k2, v2 := range info.Fields {
+				fields[k2] = v2
+			}
+		}
+		fields[k] = value
+	}
+	if fields != nil {
+		info.Fields = fields
+		redacted = true
+	}
+	for _, cause := range info.Causes {
+		if r.redactErrorInfo(cause) {
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+func (r *redaction) redactField(f *Field) {
+	if r != nil && r.fields[strings.ToLower(f.Key)] {
+		*f = String(f.Key, RedactedValue)
+		return
+	}
+	switch f.Type {
+	case FieldString:
+		if r != nil && len(r.rules) > 0 {
+			f.String = r.apply(f.String)
+		}
+	case FieldError:
+		if err, ok := f.Interface.(error); ok {
+			f.Interface = r.redactError(err)
+		}
+	case FieldAny:
+		if red, ok := f.Interface.(Redactor); ok {
+			*f = String(f.Key, red.Redact())
+		} else if r != nil && len(r.rules) > 0 && f.Interface != nil {
+			s := fmt.Sprint(f.Interface)
+			if redacted := r.apply(s); redacted != s {
+				*f = String(f.Key, redacted)
+			}
+		}
+	}
+}
+
+// redactArgs replace arguments implement Redactor by their log-safe form.
+// args is returned as is if there is no Redactor.
+func redactArgs(args []interface{}) []interface{} {
+	found := false
+	for _, arg := range args {
+		if _, ok := arg.(Redactor); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return args
+	}
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if red, ok := arg.(Redactor); ok {
+			redacted[i] = red.Redact()
+		} else {
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
+
+func AddRedactPattern(pattern, replacement string) error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.AddRedactPattern(pattern, replacement)
+}
+
+func AddRedactFields(names ...string) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.AddRedactFields(names...)
+}
@@ -0,0 +1,114 @@
+package zlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+type secretToken string
+
+func (t secretToken) Redact() string {
+	return "token(" + string(t[:2]) + "...)"
+}
+
+type loginError struct{}
+
+func (loginError) Error() string {
+	return "login failed for tom@example.com"
+}
+
+func (loginError) LogFields() map[string]interface{} {
+	return map[string]interface{}{"password": "hunter2", "user": "tom@example.com", "retry": 3}
+}
+
+func TestRedact(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_redact", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	var out bytes.Buffer
+	l.AddSink(NewWriterSink(&out, TextEncoder{}))
+
+	l.Info("token", secretToken("abcdef"))
+	if err = l.AddRedactPattern(`[\w.]+@[\w.]+`, "<email>"); err != nil {
+		t.Fatal(err)
+	}
+	if err = l.AddRedactPattern(`(`, ""); err == nil {
+		t.Error("Invalid pattern should be rejected")
+	}
+	l.AddRedactFields("Password", "authorization")
+	l.Named("auth").Info("login tom@example.com", errors.New("bad user tom@example.com"))
+	l.InfoW("login", String("password", "123456"), String("AUTHORIZATION", "Bearer x"),
+		String("user", "tom@example.com"), Any("token", secretToken("xyz123")),
+		Any("contact", []string{"tom@example.com"}))
+
+	lines := strings.Split(out.String(), "\n")
+	expects := []string{
+		"[INFO] token token(ab...)",
+		"[INFO] [auth] login <email> bad user <email>",
+		`[INFO] login password=[REDACTED] AUTHORIZATION=[REDACTED] user=<email> token=token(xy...) contact=[<email>]`,
+	}
+	for i, expect := range expects {
+		if !strings.HasSuffix(lines[i], expect) {
+			t.Error("Redacted log line is", lines[i])
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_redact_error", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	var out bytes.Buffer
+	l.AddSink(NewWriterSink(&out, JSONEncoder{}))
+	if err = l.AddRedactPattern(`[\w.]+@[\w.]+`, "<email>"); err != nil {
+		t.Fatal(err)
+	}
+	l.AddRedactFields("password")
+	var errs []error
+	l.AddHook(HookFunc(func(e *Entry) bool {
+		errs = append(errs, e.Errors...)
+		return true
+	}))
+	l.Error("auth", fmt.Errorf("handle request: %w", loginError{}))
+
+	if len(errs) != 1 || !errors.As(errs[0], &loginError{}) {
+		t.Error("Redacted error doesn't wrap the original", errs)
+	}
+	var entry struct {
+		Error struct {
+			Msg    string `json:"msg"`
+			Type   string `json:"type"`
+			Causes []struct {
+				Msg    string                 `json:"msg"`
+				Fields map[string]interface{} `json:"fields"`
+			} `json:"causes"`
+		} `json:"error"`
+	}
+	if err = json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal("Decode JSON log line failed.", err, out.String())
+	}
+	if entry.Error.Msg != "handle request: login failed for <email>" ||
+		entry.Error.Type != "*fmt.wrapError" || len(entry.Error.Causes) != 1 {
+		t.Fatal("Redacted error is", out.String())
+	}
+	cause := entry.Error.Causes[0]
+	if cause.Msg != "login failed for <email>" || cause.Fields["password"] != RedactedValue ||
+		cause.Fields["user"] != "<email>" || cause.Fields["retry"] != float64(3) {
+		t.Error("Redacted cause is", out.String())
+	}
+}
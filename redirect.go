@@ -0,0 +1,46 @@
+package zlogger
+
+import (
+	"errors"
+	"log"
+)
+
+var (
+	ErrRedirectNotSupported = errors.New("redirect stderr is not supported on this platform")
+)
+
+// RedirectStdLog make standard library log package write to logger at level.
+// Flags & prefix of standard logger are cleared, logger has its own.
+func (logger *Logger) RedirectStdLog(level uint8) {
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&LevelWriter{logger: logger, level: level, depth: stdLogDepth})
+}
+
+// RedirectStderr make fd 2 point to log file, and keep it across rotations.
+// Runtime panics & cgo output will be kept in log file without prefix.
+func (logger *Logger) RedirectStderr() error {
+	logger = logger.resolve().root()
+	logger.fileMutex.Lock()
+	defer logger.fileMutex.Unlock()
+	if err := redirectStderr(logger.file); err != nil {
+		return err
+	}
+	logger.redirectStderr.Store(true)
+	return nil
+}
+
+// RedirectStdLog make standard library log package write to default logger at level.
+// Replaced default logger is followed.
+func RedirectStdLog(level uint8) {
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(&LevelWriter{level: level, depth: stdLogDepth})
+}
+
+// RedirectStderr make fd 2 point to file of current default logger.
+func RedirectStderr() error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.RedirectStderr()
+}
@@ -0,0 +1,13 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package zlogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// redirectStderr duplicate fd of file to fd 2.
+func redirectStderr(file *os.File) error {
+	return syscall.Dup2(int(file.Fd()), 2)
+}
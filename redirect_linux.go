@@ -0,0 +1,11 @@
+package zlogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// redirectStderr duplicate fd of file to fd 2.
+func redirectStderr(file *os.File) error {
+	return syscall.Dup3(int(file.Fd()), 2, 0)
+}
@@ -0,0 +1,49 @@
+package zlogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func dupStderr() (*os.File, error) {
+	fd, err := syscall.Dup(2)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), "stderr"), nil
+}
+
+func TestRedirectStderr(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_stderr", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := dupStderr()
+	if err != nil {
+		t.Skip("Can't save stderr.", err)
+	}
+	defer func() {
+		_ = redirectStderr(saved)
+		_ = saved.Close()
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	if err = l.RedirectStderr(); err != nil {
+		t.Fatal("Redirect stderr failed.", err)
+	}
+	_, _ = fmt.Fprintln(os.Stderr, "written to stderr")
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	data, _ := io.ReadAll(f)
+	if !strings.Contains(string(data), "written to stderr") {
+		t.Error("Stderr is not redirected, log file is", string(data))
+	}
+}
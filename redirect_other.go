@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package zlogger
+
+import (
+	"os"
+)
+
+func redirectStderr(_ *os.File) error {
+	return ErrRedirectNotSupported
+}
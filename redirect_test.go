@@ -0,0 +1,39 @@
+package zlogger
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_stdlog", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	l.RedirectStdLog(LogLevelWarn)
+	log.Printf("from std %s\n", "log")
+	log.Println("second line")
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, msg := range []string{"from std log", "second line"} {
+		line := readLine(reader)
+		if !strings.Contains(line, "redirect_test.go") ||
+			!strings.HasSuffix(line, "[WARN] "+msg) {
+			t.Error("Std log line is", line)
+		}
+	}
+}
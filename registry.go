@@ -0,0 +1,86 @@
+package zlogger
+
+import (
+	"sync"
+)
+
+var (
+	// registry contain loggers registered by name.
+	registry = make(map[string]*Logger)
+	// children contain loggers returned by package level Named.
+	children = make(map[string]*Logger)
+	// placeholders contain loggers returned by Get before Register.
+	placeholders  = make(map[string]*Logger)
+	registryMutex sync.Mutex
+)
+
+// Register bind a logger to name, it can be got by Get(name).
+// Loggers returned by Get(name) before Register start to write to l.
+func Register(name string, l *Logger) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[name] = l
+	if p, ok := placeholders[name]; ok {
+		p.delegate.Store(l)
+	}
+}
+
+// Unregister remove the logger bound to name.
+// Loggers returned by Get(name) fall back to default logger.
+func Unregister(name string) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	delete(registry, name)
+	if p, ok := placeholders[name]; ok {
+		p.delegate.Store(nil)
+	}
+}
+
+// Get get the logger registered by name.
+// Libraries can call it before application configures the logger.
+// Until Register(name) is called, it writes to default logger as component [name].
+func Get(name string) *Logger {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if l, ok := registry[name]; ok {
+		return l
+	}
+	p, ok := placeholders[name]
+	if !ok {
+		p = &Logger{Name: name, placeholder: true}
+		placeholders[name] = p
+	}
+	return p
+}
+
+// resolve get the logger which really writes for placeholder.
+func (logger *Logger) resolve() *Logger {
+	if !logger.placeholder {
+		return logger
+	}
+	if logger.parent != nil {
+		return logger.parent.resolve().Named(logger.component)
+	}
+	if l := logger.delegate.Load(); l != nil {
+		return l
+	}
+	return getDefaultLogger().Named(logger.Name)
+}
+
+// acquire get the logger which really writes like resolve.
+// If placeholder writes to default logger, owner is default logger,
+// which is marked in use until owner.release, so it is not closed by retire.
+func (logger *Logger) acquire() (l, owner *Logger) {
+	if !logger.placeholder {
+		return logger, nil
+	}
+	if logger.parent != nil {
+		l, owner = logger.parent.acquire()
+		return l.Named(logger.component), owner
+	}
+	if l = logger.delegate.Load(); l != nil {
+		return l, nil
+	}
+	owner = acquireDefaultLogger()
+	return owner.Named(logger.Name), owner
+}
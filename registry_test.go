@@ -0,0 +1,108 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDefaultLoggerCreateOnce(t *testing.T) {
+	defaultMutex.Lock()
+	old := defaultLogger.Swap(nil)
+	defaultMutex.Unlock()
+	defer func() {
+		defaultMutex.Lock()
+		defaultLogger.Store(old)
+		defaultMutex.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	loggers := make([]*Logger, 10)
+	for i := range loggers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			loggers[i] = getDefaultLogger()
+		}(i)
+	}
+	wg.Wait()
+	for _, l := range loggers {
+		if l != loggers[0] {
+			t.Fatal("Default logger created more than once")
+		}
+	}
+	if old != nil {
+		loggers[0].Close()
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	audit := Get("audit")
+	if audit != Get("audit") {
+		t.Error("Get with same name should return same logger")
+	}
+	l, err := NewInternal("./", "zlogger_audit", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		Unregister("audit")
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	Register("audit", l)
+	if Get("audit") != l {
+		t.Error("Get should return registered logger")
+	}
+	audit.Info("registered later")
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	line := readLine(bufio.NewReader(f))
+	if !strings.Contains(line, "registry_test.go") ||
+		!strings.Contains(line, "[INFO] registered later") {
+		t.Error("Registered log line is", line)
+	}
+}
+
+func TestRegistryNamed(t *testing.T) {
+	// Child of placeholder is created before Register, like a package variable.
+	sql := Get("audit_named").Named("sql")
+	if sql != Get("audit_named").Named("sql") {
+		t.Error("Named with same name should return same child")
+	}
+	conn := sql.Named("conn")
+	l, err := NewInternal("./", "zlogger_audit_named", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		Unregister("audit_named")
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	Register("audit_named", l)
+	sql.Info("registered later")
+	conn.Info("nested")
+	if conn.Component() != "sql.conn" {
+		t.Error("Nested component is", conn.Component())
+	}
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	if line := readLine(reader); !strings.Contains(line, "[INFO] [sql] registered later") {
+		t.Error("Child log line is", line)
+	}
+	if line := readLine(reader); !strings.Contains(line, "[INFO] [sql.conn] nested") {
+		t.Error("Nested child log line is", line)
+	}
+}
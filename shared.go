@@ -0,0 +1,135 @@
+package zlogger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	ErrSharedNotSupported = errors.New("shared log file is not supported on this platform")
+)
+
+// pid is the process id in pid field.
+var pid = os.Getpid()
+
+// SetShared make logger safe to write the same log file with other processes,
+// which also call New with the same path & name & set shared.
+// Every entry is appended to log file by one write holding flock of
+// lock file Path/Name.lock, shared for entry up to pipeBuf, which is atomic
+// in O_APPEND mode, exclusive for larger entry.
+// Log file is rotated by the first entry of a new hour, holding exclusive flock,
+// so all processes switch to the new file at the hour boundary,
+// instead of 10 minutes ticker of each process.
+// Child loggers follow root.
+func (logger *Logger) SetShared(shared bool) error {
+	logger = logger.resolve().root()
+	if !shared {
+		logger.shared.Store(false)
+		return nil
+	}
+	if logger.lockFile.Load() == nil {
+		lock, err := os.OpenFile(filepath.Join(logger.Path, logger.Name+".lock"), os.O_RDWR|os.O_CREATE, 0666)
+		if err != nil {
+			return err
+		}
+		if err = lockFile(lock, true); err == nil {
+			err = unlockFile(lock)
+		}
+		if err != nil {
+			_ = lock.Close()
+			return err
+		}
+		if !logger.lockFile.CompareAndSwap(nil, lock) {
+			_ = lock.Close()
+		}
+	}
+	logger.shared.Store(true)
+	return nil
+}
+
+// SetPidField add pid field of process to entries,
+// so entries of processes sharing log file can be told apart.
+// Child loggers follow root.
+func (logger *Logger) SetPidField(on bool) {
+	logger.resolve().root().pidField.Store(on)
+}
+
+// localHour get hours of t in its zone since the Unix epoch,
+// it changes when the hour in log file name changes.
+func localHour(t time.Time) int64 {
+	_, offset := t.Zone()
+	return (t.Unix() + int64(offset)) / 3600
+}
+
+// rotateShared rotate log file if t is in a newer hour than log file.
+// fileMutex is held across the check & the switch, so no entry of the new hour
+// goes to the old file. Failure is retried by ticker.
+func (logger *Logger) rotateShared(t time.Time) {
+	hour := localHour(t)
+	if hour <= logger.fileHour.Load() {
+		return
+	}
+	logger.fileMutex.Lock()
+	if hour <= logger.fileHour.Load() {
+		// Rotated by another goroutine.
+		logger.fileMutex.Unlock()
+		return
+	}
+	lock := logger.lockFile.Load()
+	if lock == nil || lockFile(lock, true) != nil {
+		logger.fileMutex.Unlock()
+		return
+	}
+	oldFileHandler, errRedirect, err := logger.switchFile()
+	if err != nil {
+		// Don't retry for every entry of this hour.
+		logger.fileHour.Store(hour)
+	}
+	_ = unlockFile(lock)
+	logger.fileMutex.Unlock()
+	if err != nil {
+		logger.Error("Update logger file failed.", err)
+		return
+	}
+	logger.closeOldFile(oldFileHandler, errRedirect)
+}
+
+// writeFile write b to log file by one write.
+// In shared mode, b is written holding flock of lock file,
+// exclusive if b is larger than pipeBuf.
+// flock is taken under fileMutex, as goroutines share the lock file handler.
+func (logger *Logger) writeFile(b []byte) {
+	logger.fileMutex.Lock()
+	defer logger.fileMutex.Unlock()
+	var lock *os.File
+	if logger.shared.Load() {
+		if lock = logger.lockFile.Load(); lock != nil && lockFile(lock, len(b) > pipeBuf) != nil {
+			lock = nil
+		}
+	}
+	_, _ = logger.file.Write(b)
+	if lock != nil {
+		_ = unlockFile(lock)
+	}
+}
+
+// closeLockFile close lock file of shared mode.
+func (logger *Logger) closeLockFile() {
+	if lock := logger.lockFile.Swap(nil); lock != nil {
+		_ = lock.Close()
+	}
+}
+
+func SetShared(shared bool) error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.SetShared(shared)
+}
+
+func SetPidField(on bool) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetPidField(on)
+}
@@ -0,0 +1,7 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package zlogger
+
+// pipeBuf is PIPE_BUF of BSD, writes up to it in O_APPEND mode
+// are taken as atomic, so they don't interleave between processes.
+const pipeBuf = 512
@@ -0,0 +1,5 @@
+package zlogger
+
+// pipeBuf is PIPE_BUF of linux, writes up to it in O_APPEND mode
+// are taken as atomic, so they don't interleave between processes.
+const pipeBuf = 4096
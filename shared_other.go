@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package zlogger
+
+import (
+	"os"
+)
+
+// pipeBuf is _POSIX_PIPE_BUF, shared mode is not supported here.
+const pipeBuf = 512
+
+func lockFile(_ *os.File, _ bool) error {
+	return ErrSharedNotSupported
+}
+
+func unlockFile(_ *os.File) error {
+	return ErrSharedNotSupported
+}
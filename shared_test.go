@@ -0,0 +1,172 @@
+package zlogger
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeShared write n large & n small entries of msg in shared mode
+// from 2 goroutines.
+func writeShared(t *testing.T, path string, msg string, pidField bool, n int) {
+	l, err := NewInternal(path, "zlogger_shared", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err = l.SetShared(true); err == ErrSharedNotSupported {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	l.SetPidField(pidField)
+	var wg sync.WaitGroup
+	for j := 0; j < 2; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < n; k++ {
+				l.Info(msg)
+				l.Info("small")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestShared(t *testing.T) {
+	const n = 100
+	msgs := [2]string{strings.Repeat("x", pipeBuf*2), strings.Repeat("y", pipeBuf*3)}
+	if path := os.Getenv("ZLOGGER_SHARED_PATH"); path != "" {
+		writeShared(t, path, msgs[1], true, n)
+		return
+	}
+	// Another process writes the same log file with pid field.
+	path := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShared$")
+	cmd.Env = append(os.Environ(), "ZLOGGER_SHARED_PATH="+path)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	writeShared(t, path, msgs[0], false, n)
+	if err := cmd.Wait(); err != nil {
+		t.Fatal("Process writing log file failed.", err)
+	}
+
+	// Log file may be rotated if test crosses the hour.
+	files, err := filepath.Glob(filepath.Join(path, "zlogger_shared.*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, file := range files {
+		if strings.HasSuffix(file, ".lock") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+	}
+	if len(lines) != 8*n {
+		t.Fatal("Lines of log file is", len(lines))
+	}
+	pidField := fmt.Sprintf(" pid=%d", cmd.Process.Pid)
+	for _, line := range lines {
+		x, y := strings.Count(line, "x"), strings.Count(line, "y")
+		switch {
+		case strings.HasSuffix(line, " small"), strings.HasSuffix(line, " small"+pidField):
+		case x == len(msgs[0]) && y == 0 && !strings.Contains(line, " pid="):
+		case y == len(msgs[1]) && x == 0 && strings.HasSuffix(line, pidField):
+		default:
+			t.Fatal("Entry is interleaved, x:", x, "y:", y)
+		}
+	}
+}
+
+func TestSharedRotate(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_shared_rotate", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+		_ = os.Remove(l.Path + l.Name + ".lock")
+	}()
+	if err = l.SetShared(true); err == ErrSharedNotSupported {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	// Take log file as one of the last hour.
+	hour := l.fileHour.Load()
+	l.fileHour.Store(hour - 1)
+	l.fileMutex.Lock()
+	old := l.file
+	l.fileMutex.Unlock()
+	l.Info("rotate")
+	l.fileMutex.Lock()
+	rotated := l.file != old
+	l.fileMutex.Unlock()
+	if !rotated || l.fileHour.Load() < hour {
+		t.Fatal("Log file is not rotated")
+	}
+	// Entries of the same hour don't rotate.
+	l.fileMutex.Lock()
+	old = l.file
+	l.fileMutex.Unlock()
+	l.Info("no rotate")
+	if l.file != old {
+		t.Fatal("Log file is rotated again")
+	}
+	data, err := os.ReadFile(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "rotate") {
+		t.Fatal("Log file is", string(data))
+	}
+}
+
+func TestSharedRotateRace(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_shared_race", false, LogLevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+		_ = os.Remove(l.Path + l.Name + ".lock")
+	}()
+	if err = l.SetShared(true); err == ErrSharedNotSupported {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	// Every Info crosses the hour, while Named read FileName.
+	hour := l.fileHour.Load()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 50; k++ {
+			l.fileHour.Store(hour - 1)
+			l.Info("rotate")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for k := 0; k < 50; k++ {
+			if c := l.Named(fmt.Sprint("c", k)); c.FileName == "" {
+				t.Error("Child file name is empty")
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,84 @@
+package zlogger
+
+import (
+	"io"
+	"sync"
+)
+
+// Sink is an extra output of logger besides the log file.
+// Every entry written to log file is written to sinks too.
+type Sink interface {
+	// Write write entry to sink, entry must not be retained after return.
+	Write(e *Entry) error
+	// Sync flush buffered entries of sink.
+	Sync() error
+	// Close close sink, it is called when logger is closed.
+	Close() error
+}
+
+// AddSink add an extra output to logger.
+// Child loggers write to sinks of root.
+func (logger *Logger) AddSink(sink Sink) {
+	root := logger.resolve().root()
+	root.sinkMutex.Lock()
+	defer root.sinkMutex.Unlock()
+	var sinks []Sink
+	if old := root.sinks.Load(); old != nil {
+		sinks = append(sinks, *old...)
+	}
+	sinks = append(sinks, sink)
+	root.sinks.Store(&sinks)
+}
+
+func (logger *Logger) closeSinks() {
+	logger.sinkMutex.Lock()
+	defer logger.sinkMutex.Unlock()
+	if sinks := logger.sinks.Swap(nil); sinks != nil {
+		for _, sink := range *sinks {
+			_ = sink.Close()
+		}
+	}
+}
+
+// WriterSink encode entries & write them to an io.Writer.
+type WriterSink struct {
+	mutex   sync.Mutex
+	writer  io.Writer
+	encoder Encoder
+}
+
+// NewWriterSink create a sink write to w with encoder enc.
+func NewWriterSink(w io.Writer, enc Encoder) *WriterSink {
+	return &WriterSink{writer: w, encoder: enc}
+}
+
+func (s *WriterSink) Write(e *Entry) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = s.encoder.Encode(*buf, e)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := s.writer.Write(*buf)
+	return err
+}
+
+// Sync sync writer if it has Sync method, like *os.File.
+func (s *WriterSink) Sync() error {
+	if syncer, ok := s.writer.(interface{ Sync() error }); ok {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return syncer.Sync()
+	}
+	return nil
+}
+
+// Close do nothing, writer is owned by caller.
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+func AddSink(sink Sink) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.AddSink(sink)
+}
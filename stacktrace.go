@@ -0,0 +1,49 @@
+package zlogger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStackDepth is the most frames of a stacktrace.
+const maxStackDepth = 64
+
+// SetStacktraceLevel add stacktrace to entries at or above level.
+// Default is LogLevelOff, no stacktrace.
+// Child loggers follow the level of root.
+func (logger *Logger) SetStacktraceLevel(level uint8) {
+	logger.resolve().root().stackLevel.Store(level)
+}
+
+func (logger *Logger) GetStacktraceLevel() uint8 {
+	return logger.resolve().root().stackLevel.Load().(uint8)
+}
+
+// getStacktrace get stacktrace from call point, frames of zlogger are skipped.
+// depth is the same as runtime.Caller, like getCaller.
+// Every frame is like: pkg.Function /path/to/file.go:12
+func getStacktrace(depth int) []string {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers itself, same as runtime.Caller(depth).
+	n := runtime.Callers(depth+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		// Frames of runtime, like runtime.main & runtime.goexit, are noise.
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+func SetStacktraceLevel(level uint8) {
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetStacktraceLevel(level)
+}
@@ -0,0 +1,79 @@
+package zlogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStacktrace(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_stack", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	l.SetStacktraceLevel(LogLevelError)
+	l.Warn("no stack")
+	l.Error("with stack")
+	l.SetEncoder(JSONEncoder{})
+	l.Named("db").ErrorF("json %s", "stack")
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	if line := readLine(reader); !strings.HasSuffix(line, "[WARN] no stack") {
+		t.Error("Warn log line is", line)
+	}
+	if line := readLine(reader); !strings.HasSuffix(line, "[ERROR] with stack") {
+		t.Error("Error log line is", line)
+	}
+	line := readLine(reader)
+	if !strings.HasPrefix(line, "\tgithub.com/zhangyu0310/zlogger.TestStacktrace ") ||
+		!strings.Contains(line, "stacktrace_test.go:") {
+		t.Error("First stack frame is", line)
+	}
+	for strings.HasPrefix(line, "\t") {
+		if strings.Contains(line, "zlogger.(*Logger)") {
+			t.Error("Stack frame of zlogger is not skipped", line)
+		}
+		line = readLine(reader)
+	}
+
+	var entry struct {
+		Level      string   `json:"level"`
+		Caller     string   `json:"caller"`
+		Logger     string   `json:"logger"`
+		Msg        string   `json:"msg"`
+		Stacktrace []string `json:"stacktrace"`
+	}
+	if err = json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatal("Decode JSON log line failed.", err, line)
+	}
+	if entry.Level != "error" || entry.Logger != "db" || entry.Msg != "json stack" ||
+		!strings.HasPrefix(entry.Caller, "stacktrace_test.go:") || len(entry.Stacktrace) == 0 ||
+		!strings.HasPrefix(entry.Stacktrace[0], "github.com/zhangyu0310/zlogger.TestStacktrace ") {
+		t.Error("JSON log line is", line)
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range []string{"plain", "quote \" back \\ slash", "line\nbreak\ttab\x01",
+		"中文", "bad \xff utf8"} {
+		var decoded string
+		encoded := appendJSONString(nil, s)
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Error("Invalid JSON string", string(encoded), err)
+		}
+		if decoded != strings.ToValidUTF8(s, "\ufffd") {
+			t.Error("JSON string", string(encoded), "decoded to", decoded)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package zlogger
+
+import (
+	"errors"
+	"time"
+)
+
+// Fsync policy of log file.
+// Default is SyncPolicyNever, leave flushing to the OS.
+const (
+	SyncPolicyNever    = 0 // Never fsync automatically
+	SyncPolicyInterval = 1 // Fsync every interval on a background coroutine
+	SyncPolicyError    = 2 // Fsync after every ERROR and above
+	SyncPolicyAlways   = 3 // Fsync after every write
+)
+
+var (
+	ErrInvalidSyncInterval = errors.New("sync interval must be positive")
+	ErrLoggerClosed        = errors.New("logger is closed")
+)
+
+// Sync commit the current log file to stable storage, and flush sinks.
+// Call it before shutdown or a risky operation to make sure logs are durable.
+func (logger *Logger) Sync() error {
+	logger = logger.resolve()
+	if logger.parent != nil {
+		return logger.root().Sync()
+	}
+	err := logger.syncFile()
+	if sinks := logger.sinks.Load(); sinks != nil {
+		for _, sink := range *sinks {
+			if sErr := sink.Sync(); err == nil {
+				err = sErr
+			}
+		}
+	}
+	return err
+}
+
+// SetSyncPolicy set the fsync policy of logger.
+// Policy only fsync log file, sinks like network sink flush by themselves,
+// so logging doesn't block on them. Call Sync to flush sinks too.
+// @interval: only used by SyncPolicyInterval.
+// Child logger set the policy of the file it shares with root.
+// Return ErrLoggerClosed after Close.
+func (logger *Logger) SetSyncPolicy(policy uint8, interval time.Duration) error {
+	logger = logger.resolve()
+	if logger.parent != nil {
+		return logger.root().SetSyncPolicy(policy, interval)
+	}
+	if policy == SyncPolicyInterval && interval <= 0 {
+		return ErrInvalidSyncInterval
+	}
+	// Stop & start under one lock, so concurrent calls don't leak a coroutine.
+	logger.syncMutex.Lock()
+	defer logger.syncMutex.Unlock()
+	if logger.syncClosed {
+		return ErrLoggerClosed
+	}
+	logger.stopSyncCoroutine()
+	logger.syncPolicy.Store(policy)
+	if policy == SyncPolicyInterval {
+		logger.startSyncCoroutine(interval)
+	}
+	return nil
+}
+
+func (logger *Logger) GetSyncPolicy() uint8 {
+	logger = logger.resolve()
+	if logger.parent != nil {
+		return logger.root().GetSyncPolicy()
+	}
+	return logger.syncPolicy.Load().(uint8)
+}
+
+// syncAfterWrite fsync log file if the policy asks for it.
+func (logger *Logger) syncAfterWrite(level uint8) {
+	switch logger.GetSyncPolicy() {
+	case SyncPolicyAlways:
+	case SyncPolicyError:
+		if level < LogLevelError {
+			return
+		}
+	default:
+		return
+	}
+	// Don't log sync error by logger itself, it may loop forever.
+	_ = logger.root().syncFile()
+}
+
+// syncFile fsync log file only, used by fsync policy.
+func (logger *Logger) syncFile() error {
+	logger.fileMutex.Lock()
+	defer logger.fileMutex.Unlock()
+	return logger.file.Sync()
+}
+
+// startSyncCoroutine start interval sync coroutine, syncMutex must be held.
+func (logger *Logger) startSyncCoroutine(interval time.Duration) {
+	stop := make(chan bool)
+	logger.syncStop = stop
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+				_ = logger.syncFile()
+			}
+		}
+	}()
+}
+
+// stopSyncCoroutine stop interval sync coroutine, syncMutex must be held.
+func (logger *Logger) stopSyncCoroutine() {
+	if logger.syncStop != nil {
+		close(logger.syncStop)
+		logger.syncStop = nil
+	}
+}
+
+// closeSyncCoroutine stop interval sync coroutine, it is not started again.
+func (logger *Logger) closeSyncCoroutine() {
+	logger.syncMutex.Lock()
+	defer logger.syncMutex.Unlock()
+	logger.syncClosed = true
+	logger.stopSyncCoroutine()
+}
+
+func Sync() error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.Sync()
+}
+
+func SetSyncPolicy(policy uint8, interval time.Duration) error {
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.SetSyncPolicy(policy, interval)
+}
@@ -0,0 +1,347 @@
+package zlogger
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Format of syslog message.
+const (
+	SyslogRFC5424 = 0
+	SyslogRFC3164 = 1
+)
+
+// Facility of syslog message.
+const (
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+const (
+	// syslogEnterpriseID is the SD-ID suffix of structured data of RFC 5424.
+	syslogEnterpriseID = "zlogger@32473"
+	// Backoff of reconnection to syslog server.
+	syslogMinBackoff = 100 * time.Millisecond
+	syslogMaxBackoff = 30 * time.Second
+	// syslogTimeout is the most time of dialing & writing to syslog server,
+	// log call is blocked no longer than it.
+	syslogTimeout = time.Second
+)
+
+var (
+	ErrSyslogUnavailable = errors.New("syslog is unavailable, waiting to reconnect")
+)
+
+// syslogLocalAddresses is the addresses of local syslog socket.
+var syslogLocalAddresses = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogSeverities map log level to syslog severity.
+var syslogSeverities = [...]int{
+	LogLevelAll:   7, // debug
+	LogLevelDebug: 7, // debug
+	LogLevelInfo:  6, // informational
+	LogLevelWarn:  4, // warning
+	LogLevelError: 3, // error
+	LogLevelFatal: 2, // critical
+	LogLevelPanic: 1, // alert
+}
+
+// SyslogConfig is the config of syslog sink.
+type SyslogConfig struct {
+	Network  string // unixgram, unix, udp or tcp, empty is local syslog socket
+	Address  string // Address of syslog server
+	Format   uint8  // SyslogRFC5424 or SyslogRFC3164
+	Facility int    // Facility of message, default is FacilityUser
+	AppName  string // App name of message, default is Logger.Name
+	Hostname string // Hostname of message, default is os.Hostname
+}
+
+// SyslogSink send entries to syslog, reconnect with backoff if socket goes away.
+type SyslogSink struct {
+	config  SyslogConfig
+	pid     string
+	mutex   sync.Mutex
+	conn    net.Conn
+	local   bool          // Connected to local unix socket
+	backoff time.Duration // Wait before next reconnection
+	retryAt time.Time     // Time of next reconnection
+}
+
+// NewSyslogSink create a syslog sink, connect to syslog server lazily.
+func NewSyslogSink(config SyslogConfig) *SyslogSink {
+	if config.Facility == 0 {
+		config.Facility = FacilityUser
+	}
+	if config.AppName == "" {
+		config.AppName = "zlogger"
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+		if config.Hostname == "" {
+			config.Hostname = "-"
+		}
+	}
+	return &SyslogSink{config: config, pid: strconv.Itoa(os.Getpid())}
+}
+
+// AddSyslogSink create a syslog sink & add it to logger.
+// App name of config is default to Logger.Name.
+func (logger *Logger) AddSyslogSink(config SyslogConfig) *SyslogSink {
+	logger = logger.resolve().root()
+	if config.AppName == "" {
+		config.AppName = logger.Name
+	}
+	sink := NewSyslogSink(config)
+	logger.AddSink(sink)
+	return sink
+}
+
+func (s *SyslogSink) Write(e *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.connect(); err != nil {
+		return err
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = s.appendMessage(*buf, e)
+	_ = s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	if _, err := s.conn.Write(*buf); err != nil {
+		// Socket goes away, reconnect on next write.
+		// Server is stuck, reconnect after backoff.
+		_ = s.conn.Close()
+		s.conn = nil
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			s.fail(time.Now())
+		}
+		return err
+	}
+	return nil
+}
+
+// connect dial syslog server if not connected, with backoff after failure.
+func (s *SyslogSink) connect() error {
+	if s.conn != nil {
+		return nil
+	}
+	now := time.Now()
+	if now.Before(s.retryAt) {
+		return ErrSyslogUnavailable
+	}
+	var err error
+	if s.config.Network == "" {
+		s.conn, err = dialLocalSyslog()
+		s.local = err == nil
+	} else {
+		s.conn, err = net.DialTimeout(s.config.Network, s.config.Address, syslogTimeout)
+		s.local = s.config.Network == "unix" || s.config.Network == "unixgram"
+	}
+	if err != nil {
+		s.conn = nil
+		s.fail(now)
+		return err
+	}
+	s.backoff = 0
+	return nil
+}
+
+// fail wait a backoff before next connection,
+// so a dead server costs a log call syslogTimeout at most once per backoff.
+func (s *SyslogSink) fail(now time.Time) {
+	if s.backoff == 0 {
+		s.backoff = syslogMinBackoff
+	} else if s.backoff < syslogMaxBackoff {
+		s.backoff *= 2
+	}
+	s.retryAt = now.Add(s.backoff)
+}
+
+func dialLocalSyslog() (net.Conn, error) {
+	var err error
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, address := range syslogLocalAddresses {
+			var conn net.Conn
+			conn, err = net.Dial(network, address)
+			if err == nil {
+				return conn, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// appendMessage append entry to buf as syslog message.
+func (s *SyslogSink) appendMessage(buf []byte, e *Entry) []byte {
+	if s.config.Format == SyslogRFC3164 {
+		buf = s.append3164(buf, e)
+		if s.config.Network == "tcp" {
+			buf = append(buf, '\n')
+		}
+		return buf
+	}
+	if s.config.Network != "tcp" {
+		return s.append5424(buf, e)
+	}
+	// Octet counting framing of RFC 6587.
+	msg := getBuffer()
+	defer putBuffer(msg)
+	*msg = s.append5424(*msg, e)
+	buf = strconv.AppendInt(buf, int64(len(*msg)), 10)
+	buf = append(buf, ' ')
+	return append(buf, *msg...)
+}
+
+func (s *SyslogSink) appendPriority(buf []byte, level uint8) []byte {
+	severity := 7
+	if int(level) < len(syslogSeverities) {
+		severity = syslogSeverities[level]
+	}
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(s.config.Facility*8+severity), 10)
+	return append(buf, '>')
+}
+
+// append5424 append entry as RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+// Caller & fields are in structured data, component is MSGID.
+func (s *SyslogSink) append5424(buf []byte, e *Entry) []byte {
+	buf = s.appendPriority(buf, e.Level)
+	buf = append(buf, "1 "...)
+	buf = e.Time.AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, s.config.Hostname, 255)
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, s.config.AppName, 48)
+	buf = append(buf, ' ')
+	buf = append(buf, s.pid...)
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, e.Component, 32)
+	buf = append(buf, ' ')
+	if e.Caller == "" && len(e.Fields) == 0 {
+		buf = append(buf, '-')
+	} else {
+		buf = append(buf, '[')
+		buf = append(buf, syslogEnterpriseID...)
+		if e.Caller != "" {
+			buf = append(buf, ` caller="`...)
+			buf = appendSDValue(buf, e.Caller)
+			buf = append(buf, '"')
+		}
+		for i := range e.Fields {
+			buf = append(buf, ' ')
+			buf = appendSyslogName(buf, e.Fields[i].Key, 32)
+			buf = append(buf, `="`...)
+			value := getBuffer()
+			*value = appendFieldValue(*value, &e.Fields[i], appendString)
+			buf = appendSDValue(buf, string(*value))
+			putBuffer(value)
+			buf = append(buf, '"')
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, ' ')
+	return s.appendText(buf, e)
+}
+
+// append3164 append entry as RFC 3164 message:
+// <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+// Hostname is omitted for local socket, fields follow message as key=value.
+func (s *SyslogSink) append3164(buf []byte, e *Entry) []byte {
+	buf = s.appendPriority(buf, e.Level)
+	buf = e.Time.AppendFormat(buf, time.Stamp)
+	buf = append(buf, ' ')
+	if !s.local {
+		buf = appendSyslogName(buf, s.config.Hostname, 255)
+		buf = append(buf, ' ')
+	}
+	buf = appendSyslogName(buf, s.config.AppName, 32)
+	buf = append(buf, '[')
+	buf = append(buf, s.pid...)
+	buf = append(buf, "]: "...)
+	if e.Caller != "" {
+		buf = append(buf, e.Caller...)
+		buf = append(buf, ": "...)
+	}
+	if e.Component != "" {
+		buf = append(buf, '[')
+		buf = append(buf, e.Component...)
+		buf = append(buf, "] "...)
+	}
+	buf = s.appendText(buf, e)
+	for i := range e.Fields {
+		buf = append(buf, ' ')
+		buf = appendFieldKey(buf, e.Fields[i].Key)
+		buf = append(buf, '=')
+		buf = appendTextValue(buf, &e.Fields[i])
+	}
+	return buf
+}
+
+// appendText append message & errors of entry, newlines are escaped.
+func (s *SyslogSink) appendText(buf []byte, e *Entry) []byte {
+	return appendErrorMessage(buf, e, func(buf []byte, s string) []byte {
+		return appendEscaped(buf, s, "")
+	})
+}
+
+// appendSyslogName append a header field of syslog, "-" if it is empty.
+// Only printable US-ASCII without space, '=', ']' & '"' is kept, up to max bytes.
+func appendSyslogName(buf []byte, name string, max int) []byte {
+	if name == "" {
+		return append(buf, '-')
+	}
+	for i := 0; i < len(name) && i < max; i++ {
+		c := name[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+// appendSDValue append value of structured data, '"', '\' & ']' are escaped.
+func appendSDValue(buf []byte, value string) []byte {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '"' || c == '\\' || c == ']' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, c)
+	}
+	return buf
+}
+
+// appendString append s as is.
+func appendString(buf []byte, s string) []byte {
+	return append(buf, s...)
+}
+
+// Sync do nothing, syslog is not buffered.
+func (s *SyslogSink) Sync() error {
+	return nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
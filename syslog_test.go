@@ -0,0 +1,116 @@
+package zlogger
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readDatagram(t *testing.T, conn net.PacketConn) string {
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal("Read syslog message failed.", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("Can't listen udp.", err)
+	}
+	defer func() { _ = conn.Close() }()
+	l, err := NewInternal("./", "zlogger_syslog", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	l.AddSyslogSink(SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(),
+		Facility: FacilityLocal0, Hostname: "web-1"})
+	l.Named("db").WarnW("slow \"query\"", String("sql", "select ]"))
+
+	msg := readDatagram(t, conn)
+	head := "<132>1 "
+	if !strings.HasPrefix(msg, head) {
+		t.Error("Syslog priority is wrong", msg)
+	}
+	tail := " web-1 zlogger_syslog " + strconv.Itoa(os.Getpid()) + ` db [zlogger@32473 caller="syslog_test.go:`
+	if !strings.Contains(msg, tail) ||
+		!strings.HasSuffix(msg, ` sql="select \]"] slow "query"`) {
+		t.Error("RFC 5424 message is", msg)
+	}
+}
+
+func TestSyslogUnixgramReconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	sink := NewSyslogSink(SyslogConfig{Network: "unixgram", Address: path,
+		Format: SyslogRFC3164, AppName: "app"})
+	defer func() { _ = sink.Close() }()
+	e := &Entry{Level: LogLevelError, Time: time.Now(), Caller: "foo.go:12",
+		Message: "line\nbreak", Fields: []Field{Int("code", 5)}}
+	if err := sink.Write(e); err == nil {
+		t.Fatal("Write without syslog server should fail")
+	}
+	if err := sink.Write(e); err != ErrSyslogUnavailable {
+		t.Error("Write in backoff should be skipped", err)
+	}
+
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skip("Can't listen unixgram.", err)
+	}
+	defer func() { _ = conn.Close() }()
+	time.Sleep(syslogMinBackoff)
+	if err = sink.Write(e); err != nil {
+		t.Fatal("Reconnect syslog failed.", err)
+	}
+	msg := readDatagram(t, conn)
+	if !strings.HasPrefix(msg, "<11>") ||
+		!strings.HasSuffix(msg, " app["+strconv.Itoa(os.Getpid())+`]: foo.go:12: line\nbreak code=5`) {
+		t.Error("RFC 3164 message is", msg)
+	}
+}
+
+func TestSyslogTCPStuck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("Can't listen tcp.", err)
+	}
+	defer func() { _ = ln.Close() }()
+	// Server accept connection but never read it.
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer func() { _ = conn.Close() }()
+			time.Sleep(10 * time.Second)
+		}
+	}()
+	sink := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String()})
+	defer func() { _ = sink.Close() }()
+	e := &Entry{Level: LogLevelInfo, Time: time.Now(), Message: strings.Repeat("x", 64<<10)}
+	for i := 0; ; i++ {
+		if i == 10000 {
+			t.Fatal("Write to stuck server never fail")
+		}
+		start := time.Now()
+		err = sink.Write(e)
+		if cost := time.Since(start); cost > 2*syslogTimeout {
+			t.Fatal("Write to stuck server blocks", cost)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err = sink.Write(e); err != ErrSyslogUnavailable {
+		t.Error("Write after timeout should wait backoff", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package zlogger
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal report whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(),
+		syscall.TIOCGETA, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !windows
+
+package zlogger
+
+import (
+	"os"
+)
+
+// isTerminal report whether f is a terminal.
+// Terminal can't be told from other char devices here, so color is off.
+func isTerminal(_ *os.File) bool {
+	return false
+}
@@ -0,0 +1,12 @@
+package zlogger
+
+import (
+	"os"
+	"syscall"
+)
+
+// isTerminal report whether f is a console.
+func isTerminal(f *os.File) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) == nil
+}
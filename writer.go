@@ -0,0 +1,57 @@
+package zlogger
+
+import (
+	"log"
+	"strings"
+)
+
+const (
+	// writerDepth is caller depth of direct call to LevelWriter.Write.
+	// logN -> Write -> caller
+	writerDepth = 3
+	// stdLogDepth is caller depth of log.Printf & co from LevelWriter.Write.
+	// logN -> Write -> (*log.Logger).output -> (*log.Logger).Printf -> caller
+	stdLogDepth = 5
+)
+
+// LevelWriter is an io.Writer, every write become one log entry at level.
+// Trailing newlines are trimmed.
+// Use it for libraries which take an io.Writer, like database drivers.
+type LevelWriter struct {
+	SplitLines bool // Every line of a multi-line write become one log entry
+
+	logger *Logger // Logger to write, nil for default logger
+	level  uint8   // The level of log entry
+	depth  int     // The depth of caller from Write
+}
+
+func (w *LevelWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	l := w.logger
+	if l == nil {
+		l = acquireDefaultLogger()
+		defer l.release()
+	}
+	if !w.SplitLines {
+		l.logN(w.level, w.depth, msg)
+		return len(p), nil
+	}
+	for _, line := range strings.Split(msg, "\n") {
+		if line == "" {
+			continue
+		}
+		l.logN(w.level, w.depth, line)
+	}
+	return len(p), nil
+}
+
+// Writer get an io.Writer which write to logger at level.
+func (logger *Logger) Writer(level uint8) *LevelWriter {
+	return &LevelWriter{logger: logger, level: level, depth: writerDepth}
+}
+
+// StdLogger get a *log.Logger which write to logger at level.
+// Use it for libraries which take a *log.Logger, like net/http.Server.ErrorLog.
+func (logger *Logger) StdLogger(level uint8) *log.Logger {
+	return log.New(&LevelWriter{logger: logger, level: level, depth: stdLogDepth}, "", 0)
+}
@@ -0,0 +1,39 @@
+package zlogger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_writer", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	w := l.Writer(LogLevelInfo)
+	_, _ = w.Write([]byte("single write\n\n"))
+	w.SplitLines = true
+	_, _ = w.Write([]byte("first line\nsecond line\n"))
+	l.StdLogger(LogLevelError).Printf("std logger %d", 1)
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	for _, msg := range []string{"[INFO] single write", "[INFO] first line",
+		"[INFO] second line", "[ERROR] std logger 1"} {
+		line := readLine(reader)
+		if !strings.Contains(line, "writer_test.go") ||
+			!strings.HasSuffix(line, msg) {
+			t.Error("Writer log line is", line)
+		}
+	}
+}
@@ -8,10 +8,10 @@ package zlogger
 import (
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -30,23 +30,67 @@ const (
 )
 
 var (
-	ErrPathIsNotDir = errors.New("path is not dir")
+	ErrPathIsNotDir    = errors.New("path is not dir")
+	ErrInvalidLogLevel = errors.New("invalid log level")
 )
 
-// defaultLogger is a default logger for sample function.
-var defaultLogger *Logger
+var (
+	// defaultLogger is a default logger for sample function.
+	defaultLogger atomic.Pointer[Logger]
+	// defaultMutex make creation & replacement of defaultLogger serial.
+	defaultMutex sync.Mutex
+)
 
 // Logger contain log of go & file handler.
 // Use logger.xxx() to log & set right prefix.
 type Logger struct {
-	logger     *log.Logger  // Use Logger of golang
 	file       *os.File     // File handler of Logger
+	fileMutex  sync.Mutex   // Protect file handler & FileName from update & sync
 	Path       string       // The path of Logger
 	Name       string       // The name of Logger without day
 	FileName   string       // The name of Logger with day info
 	close      chan bool    // The logger is closed
 	autoUpdate bool         // logger can auto update log file
 	logLevel   atomic.Value // The level of log to print
+	syncPolicy atomic.Value // The fsync policy of log file
+	syncMutex  sync.Mutex   // Protect sync coroutine start & stop
+	syncStop   chan bool    // Stop the interval sync coroutine
+	syncClosed bool         // Sync coroutine is not started after Close
+
+	parent        *Logger            // Parent of child logger, nil for root
+	component     string             // Component name of child logger
+	levelOverride atomic.Bool        // Child logger has own log level
+	compMutex     sync.Mutex         // Protect components registry
+	components    map[string]*Logger // Registry of child loggers (root only)
+
+	placeholder bool                   // Logger is returned by Get before Register
+	delegate    atomic.Pointer[Logger] // Registered logger of placeholder
+
+	refs atomic.Int64 // In-flight package level calls on default logger
+
+	redirectStderr atomic.Bool // Stderr follows log file on rotation
+
+	callerFormat atomic.Value // The format of caller info (root only)
+	callerFunc   atomic.Bool  // Add function name to caller info (root only)
+
+	encoder    atomic.Pointer[Encoder] // The encoder of log entry (root only)
+	stackLevel atomic.Value            // Add stacktrace at or above it (root only)
+
+	sinks     atomic.Pointer[[]Sink] // Extra outputs besides log file (root only)
+	sinkMutex sync.Mutex             // Protect sinks from concurrent add
+
+	redaction      atomic.Pointer[redaction] // Redaction rules (root only)
+	redactionMutex sync.Mutex                // Protect rules from concurrent add
+
+	hooks     atomic.Pointer[[]Hook] // Hooks of entries (root only)
+	hookMutex sync.Mutex             // Protect hooks from concurrent add
+
+	recorder atomic.Pointer[flightRecorder] // Recent entries in memory (root only)
+
+	shared   atomic.Bool             // Log file is shared by processes (root only)
+	lockFile atomic.Pointer[os.File] // Lock file of shared mode (root only)
+	fileHour atomic.Int64            // Local hour of log file, see localHour
+	pidField atomic.Bool             // Add pid field to entries (root only)
 }
 
 // New create a new logger handler.
@@ -54,21 +98,38 @@ type Logger struct {
 // @name: prefix of logs.
 // Log file name just have year-month-day
 // Time of logs record is microseconds.
-func New(path, name string, autoUpdate bool, logLevel uint8) (err error) {
-	if defaultLogger != nil {
-		defaultLogger.Close()
+func New(path, name string, autoUpdate bool, logLevel uint8) error {
+	l, err := NewInternal(path, name, autoUpdate, logLevel)
+	if err != nil {
+		return err
 	}
-	defaultLogger, err = NewInternal(path, name, autoUpdate, logLevel)
-	return
+	defaultMutex.Lock()
+	old := defaultLogger.Swap(l)
+	defaultMutex.Unlock()
+	if old != nil {
+		old.retire()
+	}
+	return nil
 }
 
-// defaultNew create a default logger handler.
-// If defaultLogger is nil and log function been called, this function will be called.
-func defaultNew() {
-	err := New("./", "zlogger", false, LogLevelAll)
+// getDefaultLogger get the default logger handler.
+// If defaultLogger is nil and log function been called, create it with default config.
+// Concurrent first calls create only one logger.
+func getDefaultLogger() *Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+	l, err := NewInternal("./", "zlogger", false, LogLevelAll)
 	if err != nil {
 		panic(err)
 	}
+	defaultLogger.Store(l)
+	return l
 }
 
 func getLogFileName(name string) string {
@@ -84,7 +145,12 @@ func NewInternal(path, name string, autoUpdate bool, logLevel uint8) (*Logger, e
 		autoUpdate: autoUpdate,
 	}
 	l.SetLogLevel(logLevel)
+	l.syncPolicy.Store(uint8(SyncPolicyNever))
+	l.callerFormat.Store(uint8(CallerShort))
+	l.SetEncoder(TextEncoder{})
+	l.stackLevel.Store(uint8(LogLevelOff))
 	l.FileName = getLogFileName(name)
+	l.fileHour.Store(localHour(time.Now()))
 	filePath := filepath.Join(l.Path, l.FileName)
 
 	info, err := os.Stat(path)
@@ -105,8 +171,7 @@ func NewInternal(path, name string, autoUpdate bool, logLevel uint8) (*Logger, e
 	if err != nil {
 		return nil, err
 	}
-	l.logger = log.New(l.file, "", log.LstdFlags|log.Lmicroseconds)
-	if l.logger != nil && autoUpdate {
+	if autoUpdate {
 		go func() {
 			// Check time and update logger file.
 			t := time.NewTicker(time.Minute * 10)
@@ -116,7 +181,7 @@ func NewInternal(path, name string, autoUpdate bool, logLevel uint8) (*Logger, e
 				case <-l.close:
 					return
 				case <-t.C:
-					if l.FileName != getLogFileName(l.Name) {
+					if l.fileName() != getLogFileName(l.Name) {
 						if err := l.updateLoggerFile(); err != nil {
 							l.Error("Update logger file failed.", err)
 							break
@@ -130,62 +195,168 @@ func NewInternal(path, name string, autoUpdate bool, logLevel uint8) (*Logger, e
 }
 
 func ForceUpdateLoggerFile() error {
-	return defaultLogger.updateLoggerFile()
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.updateLoggerFile()
 }
 
 // updateLoggerFile update the log file name. (Date suffix)
 func (logger *Logger) updateLoggerFile() error {
-	logger.FileName = getLogFileName(logger.Name)
-	filePath := filepath.Join(logger.Path, logger.FileName)
-	// Create new file handler & new logger
-	nFile, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	logger.fileMutex.Lock()
+	oldFileHandler, errRedirect, err := logger.switchFile()
+	logger.fileMutex.Unlock()
 	if err != nil {
 		return err
 	}
-	// Set new file handler/logger to old logger & close old file handler.
-	logger.logger.SetOutput(nFile)
-	oldFileHandler := logger.file
+	logger.closeOldFile(oldFileHandler, errRedirect)
+	return nil
+}
+
+// switchFile open log file of this hour & switch logger to it,
+// return the old file handler, which is not closed yet.
+// fileMutex must be held.
+func (logger *Logger) switchFile() (oldFileHandler *os.File, errRedirect, err error) {
+	fileName := getLogFileName(logger.Name)
+	// Create new file handler & new logger
+	nFile, err := os.OpenFile(filepath.Join(logger.Path, fileName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Set new file handler to logger.
+	oldFileHandler = logger.file
 	logger.file = nFile
-	if err := oldFileHandler.Close(); err != nil {
-		logger.Error("Old logger file handler close failed.", err)
+	logger.FileName = fileName
+	logger.fileHour.Store(localHour(time.Now()))
+	if logger.redirectStderr.Load() {
+		errRedirect = redirectStderr(nFile)
 	}
-	return nil
+	return oldFileHandler, errRedirect, nil
 }
 
-// getFileAndLinePrefix get file name & line of call function.
-func getFileAndLinePrefix(depth int) string {
-	_, file, line, ok := runtime.Caller(depth)
-	if !ok {
-		file = "???"
-		line = 0
+// closeOldFile close old file handler after switchFile, fileMutex must not be held.
+func (logger *Logger) closeOldFile(oldFileHandler *os.File, errRedirect error) {
+	if errRedirect != nil {
+		logger.Error("Redirect stderr to new logger file failed.", errRedirect)
 	}
-	// I don't like log path. Use short.
-	short := file
-	for i := len(file) - 1; i > 0; i-- {
-		if file[i] == '/' {
-			short = file[i+1:]
-			break
-		}
+	if err := oldFileHandler.Close(); err != nil {
+		logger.Error("Old logger file handler close failed.", err)
 	}
-	return fmt.Sprintf("%s:%d:", short, line)
 }
 
+// fileName get name of log file now, it is changed by rotation.
+func (logger *Logger) fileName() string {
+	logger.fileMutex.Lock()
+	defer logger.fileMutex.Unlock()
+	return logger.FileName
+}
+
+// SetLogLevel set log level of logger.
+// For child logger, it overrides the level of parent.
 func (logger *Logger) SetLogLevel(logLevel uint8) {
+	logger = logger.resolve()
 	logger.logLevel.Store(logLevel)
+	if logger.parent != nil {
+		logger.levelOverride.Store(true)
+	}
 }
 
+// GetLogLevel get effective log level of logger.
+// Child logger without own level follows its parent.
 func (logger *Logger) GetLogLevel() uint8 {
+	logger = logger.resolve()
+	if logger.parent != nil && !logger.levelOverride.Load() {
+		return logger.parent.GetLogLevel()
+	}
 	return logger.logLevel.Load().(uint8)
 }
 
-func getMsgSlice(prefix, logType string, msg []interface{}) []interface{} {
-	msgLocal := make([]interface{}, 0, 4)
-	msgLocal = append(msgLocal, prefix)
-	msgLocal = append(msgLocal, logType)
-	msgLocal = append(msgLocal, msg...)
-	return msgLocal
+// output build entry of log at level, and write it to log file.
+// n is the depth of caller like DebugN.
+// errs are error values split from msg, encoded by encoder,
+// offsets are where they are in msg.
+// fields are copied, so they don't escape to heap.
+func (logger *Logger) output(level uint8, n int, msg string, errs []error, offsets []int, fields []Field) {
+	root := logger.root()
+	e := getEntry()
+	e.Level = level
+	e.Time = time.Now()
+	e.Component = logger.component
+	e.Message = msg
+	e.Errors = errs
+	e.ErrorOffsets = offsets
+	if root.pidField.Load() {
+		e.Fields = append(e.Fields, Int("pid", pid))
+	}
+	e.Fields = append(e.Fields, fields...)
+	e.Caller, e.Function = logger.getCaller(n)
+	if level >= root.GetStacktraceLevel() {
+		e.Stack = getStacktrace(n)
+	}
+	root.redact(e)
+	if logger.GetLogLevel() > level {
+		// Entry below log level is built for flight recorder only.
+		root.record(e)
+	} else if root.fireHooks(e) {
+		root.write(e)
+		logger.syncAfterWrite(level)
+	}
+	putEntry(e)
+}
+
+// write encode entry & write it to log file.
+// Flight recorder is dumped before entry at or above LogLevelError.
+func (logger *Logger) write(e *Entry) {
+	if logger.shared.Load() {
+		logger.rotateShared(e.Time)
+	}
+	buf := getBuffer()
+	r := logger.recorder.Load()
+	if r != nil && e.Level >= LogLevelError {
+		*buf = r.dump(*buf, logger.GetEncoder())
+	}
+	start := len(*buf)
+	*buf = logger.GetEncoder().Encode(*buf, e)
+	if r != nil {
+		r.add((*buf)[start:], true)
+	}
+	logger.writeFile(*buf)
+	putBuffer(buf)
+	if sinks := logger.sinks.Load(); sinks != nil {
+		for _, sink := range *sinks {
+			// Don't log sink error by logger itself, it may loop forever.
+			_ = sink.Write(e)
+		}
+	}
+}
+
+// sprintln format msg like fmt.Println, without the newline.
+// Values implement Redactor are replaced by their log-safe form.
+func sprintln(msg []interface{}) string {
+	msg = redactArgs(msg)
+	if len(msg) == 1 {
+		if s, ok := msg[0].(string); ok {
+			return s
+		}
+	}
+	s := fmt.Sprintln(msg...)
+	return s[:len(s)-1]
+}
+
+// sprintf format v like fmt.Sprintf, skip it if format has no verb.
+// Values implement Redactor are replaced by their log-safe form.
+func sprintf(format string, v []interface{}) string {
+	v = redactArgs(v)
+	if len(v) == 0 && strings.IndexByte(format, '%') < 0 {
+		return format
+	}
+	return fmt.Sprintf(format, v...)
 }
 
+// Debug log msg at LogLevelDebug, formatted like fmt.Println.
+// msg is boxed to interface{} by caller before level is checked, so
+// non-constant arguments allocate even if level is disabled, same for
+// DebugF & Info, Warn, Error & co. Use DebugW with typed fields or DebugFn
+// on hot paths, they don't allocate if level is disabled.
 func (logger *Logger) Debug(msg ...interface{}) {
 	logger.DebugN(3, msg...)
 }
@@ -195,21 +366,22 @@ func (logger *Logger) DebugF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) DebugN(n int, msg ...interface{}) {
-	if logger.GetLogLevel() > LogLevelDebug {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelDebug) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[DEBUG]", msg)
-	logger.logger.Println(msgLocal...)
+	s, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelDebug, n+1, s, errs, offsets, nil)
 }
 
 func (logger *Logger) DebugNF(n int, format string, v ...interface{}) {
-	if logger.GetLogLevel() > LogLevelDebug {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelDebug) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[DEBUG]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelDebug, n+1, sprintf(format, v), nil, nil, nil)
 }
 
 func (logger *Logger) Info(msg ...interface{}) {
@@ -221,21 +393,22 @@ func (logger *Logger) InfoF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) InfoN(n int, msg ...interface{}) {
-	if logger.GetLogLevel() > LogLevelInfo {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelInfo) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[INFO]", msg)
-	logger.logger.Println(msgLocal...)
+	s, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelInfo, n+1, s, errs, offsets, nil)
 }
 
 func (logger *Logger) InfoNF(n int, format string, v ...interface{}) {
-	if logger.GetLogLevel() > LogLevelInfo {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelInfo) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[INFO]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelInfo, n+1, sprintf(format, v), nil, nil, nil)
 }
 
 func (logger *Logger) Warn(msg ...interface{}) {
@@ -247,21 +420,22 @@ func (logger *Logger) WarnF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) WarnN(n int, msg ...interface{}) {
-	if logger.GetLogLevel() > LogLevelWarn {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelWarn) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[WARN]", msg)
-	logger.logger.Println(msgLocal...)
+	s, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelWarn, n+1, s, errs, offsets, nil)
 }
 
 func (logger *Logger) WarnNF(n int, format string, v ...interface{}) {
-	if logger.GetLogLevel() > LogLevelWarn {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelWarn) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[WARN]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelWarn, n+1, sprintf(format, v), nil, nil, nil)
 }
 
 func (logger *Logger) Error(msg ...interface{}) {
@@ -273,21 +447,22 @@ func (logger *Logger) ErrorF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) ErrorN(n int, msg ...interface{}) {
-	if logger.GetLogLevel() > LogLevelError {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelError) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[ERROR]", msg)
-	logger.logger.Println(msgLocal...)
+	s, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelError, n+1, s, errs, offsets, nil)
 }
 
 func (logger *Logger) ErrorNF(n int, format string, v ...interface{}) {
-	if logger.GetLogLevel() > LogLevelError {
+	logger, owner := logger.acquire()
+	defer owner.release()
+	if logger.disabled(LogLevelError) {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[ERROR]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelError, n+1, sprintf(format, v), nil, nil, nil)
 }
 
 func (logger *Logger) Fatal(msg ...interface{}) {
@@ -299,21 +474,23 @@ func (logger *Logger) FatalF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) FatalN(n int, msg ...interface{}) {
+	logger, owner := logger.acquire()
+	defer owner.release()
 	if logger.GetLogLevel() > LogLevelFatal {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[FATAL]", msg)
-	logger.logger.Fatalln(msgLocal...)
+	s, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelFatal, n+1, s, errs, offsets, nil)
+	logger.exit()
 }
 
 func (logger *Logger) FatalNF(n int, format string, v ...interface{}) {
+	logger, owner := logger.acquire()
+	defer owner.release()
 	if logger.GetLogLevel() > LogLevelFatal {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[FATAL]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelFatal, n+1, sprintf(format, v), nil, nil, nil)
 }
 
 func (logger *Logger) Panic(msg ...interface{}) {
@@ -325,27 +502,64 @@ func (logger *Logger) PanicF(format string, v ...interface{}) {
 }
 
 func (logger *Logger) PanicN(n int, msg ...interface{}) {
+	logger, owner := logger.acquire()
+	defer owner.release()
 	if logger.GetLogLevel() > LogLevelPanic {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	msgLocal := getMsgSlice(prefix, "[PANIC]", msg)
-	logger.logger.Panicln(msgLocal...)
+	text, errs, offsets := splitErrors(msg)
+	logger.output(LogLevelPanic, n+1, text, errs, offsets, nil)
+	panic(sprintln(msg))
 }
 
 func (logger *Logger) PanicNF(n int, format string, v ...interface{}) {
+	logger, owner := logger.acquire()
+	defer owner.release()
 	if logger.GetLogLevel() > LogLevelFatal {
 		return
 	}
-	prefix := getFileAndLinePrefix(n)
-	f := fmt.Sprintf("%s %s %s\n", prefix, "[PANIC]", format)
-	logger.logger.Printf(f, v...)
+	logger.output(LogLevelPanic, n+1, sprintf(format, v), nil, nil, nil)
+}
+
+// exit close sinks & exit after fatal entry,
+// so entries queued by sinks like network sink are not lost.
+func (logger *Logger) exit() {
+	logger.root().closeSinks()
+	os.Exit(1)
+}
+
+// logN log msg at level, n is the depth of caller like DebugN.
+func (logger *Logger) logN(level uint8, n int, msg ...interface{}) {
+	switch level {
+	case LogLevelDebug:
+		logger.DebugN(n+1, msg...)
+	case LogLevelInfo:
+		logger.InfoN(n+1, msg...)
+	case LogLevelWarn:
+		logger.WarnN(n+1, msg...)
+	case LogLevelError:
+		logger.ErrorN(n+1, msg...)
+	case LogLevelFatal:
+		logger.FatalN(n+1, msg...)
+	case LogLevelPanic:
+		logger.PanicN(n+1, msg...)
+	}
 }
 
 // Close stop update log file coroutine & close log file handler.
 // You don't need to call this function on exit.
+// Child logger & logger from Get don't own the file, close them do nothing.
 func (logger *Logger) Close() {
+	if logger.parent != nil || logger.placeholder {
+		return
+	}
+	logger.closeSyncCoroutine()
+	logger.fileMutex.Lock()
+	_ = logger.file.Sync()
 	_ = logger.file.Close()
+	logger.fileMutex.Unlock()
+	logger.closeLockFile()
+	logger.closeSinks()
 	if logger.autoUpdate {
 		logger.close <- true
 		close(logger.close)
@@ -353,101 +567,87 @@ func (logger *Logger) Close() {
 }
 
 func SetLogLevel(logLevel uint8) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.SetLogLevel(logLevel)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.SetLogLevel(logLevel)
 }
 
 func GetLogLevel() uint8 {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	return defaultLogger.GetLogLevel()
+	l := acquireDefaultLogger()
+	defer l.release()
+	return l.GetLogLevel()
 }
 
 func Debug(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.DebugN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.DebugN(3, msg...)
 }
 
 func Info(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.InfoN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.InfoN(3, msg...)
 }
 
 func Warn(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.WarnN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.WarnN(3, msg...)
 }
 
 func Error(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.ErrorN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.ErrorN(3, msg...)
 }
 
 func Fatal(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.FatalN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.FatalN(3, msg...)
 }
 
 func Panic(msg ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.PanicN(3, msg...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.PanicN(3, msg...)
 }
 
 func DebugF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.DebugNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.DebugNF(3, format, v...)
 }
 
 func InfoF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.InfoNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.InfoNF(3, format, v...)
 }
 
 func WarnF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.WarnNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.WarnNF(3, format, v...)
 }
 
 func ErrorF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.ErrorNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.ErrorNF(3, format, v...)
 }
 
 func FatalF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.FatalNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.FatalNF(3, format, v...)
 }
 
 func PanicF(format string, v ...interface{}) {
-	if defaultLogger == nil {
-		defaultNew()
-	}
-	defaultLogger.PanicNF(3, format, v...)
+	l := acquireDefaultLogger()
+	defer l.release()
+	l.PanicNF(3, format, v...)
 }
 
 func LogLevel2Str(level uint8) string {
@@ -472,3 +672,13 @@ func LogLevel2Str(level uint8) string {
 		return "unknown"
 	}
 }
+
+// Str2LogLevel get level from its name, like "info" or "INFO".
+func Str2LogLevel(s string) (uint8, error) {
+	for level := uint8(LogLevelAll); level <= LogLevelOff; level++ {
+		if strings.EqualFold(s, LogLevel2Str(level)) {
+			return level, nil
+		}
+	}
+	return LogLevelOff, ErrInvalidLogLevel
+}
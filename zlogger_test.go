@@ -44,7 +44,7 @@ func TestMultiOpenAndWrite(t *testing.T) {
 	}()
 	wg.Wait()
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 }
 
 func newForTest(t *testing.T) {
@@ -120,7 +120,7 @@ func TestUpdateLoggerFile(t *testing.T) {
 	end := time.Now().UnixNano()
 	t.Log("Time cost:", end-begin)
 
-	f, _ := os.Open(defaultLogger.Path + defaultLogger.FileName)
+	f, _ := os.Open(getDefaultLogger().Path + getDefaultLogger().FileName)
 	defer func(f *os.File) {
 		err := f.Close()
 		if err != nil {
@@ -235,7 +235,7 @@ func TestUpdateLoggerFile(t *testing.T) {
 
 	t.Log("Check success.")
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 }
 
 func readLine(reader *bufio.Reader) string {
@@ -244,7 +244,7 @@ func readLine(reader *bufio.Reader) string {
 }
 
 func checkResult(t *testing.T, level uint8) {
-	f, _ := os.Open(defaultLogger.Path + defaultLogger.FileName)
+	f, _ := os.Open(getDefaultLogger().Path + getDefaultLogger().FileName)
 	defer func(f *os.File) {
 		err := f.Close()
 		if err != nil {
@@ -404,7 +404,7 @@ func writeTestLog(level uint8) {
 
 func TestSetLogLevel(t *testing.T) {
 	newForTest(t)
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err := ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -412,7 +412,7 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelAll)
 	checkResult(t, LogLevelAll)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err = ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -420,7 +420,7 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelDebug)
 	checkResult(t, LogLevelDebug)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err = ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -428,7 +428,7 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelInfo)
 	checkResult(t, LogLevelInfo)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err = ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -436,7 +436,7 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelWarn)
 	checkResult(t, LogLevelWarn)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err = ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -444,7 +444,7 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelError)
 	checkResult(t, LogLevelError)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
 	err = ForceUpdateLoggerFile()
 	if err != nil {
 		t.Fatal("Update log file failed.", err)
@@ -452,5 +452,194 @@ func TestSetLogLevel(t *testing.T) {
 	writeTestLog(LogLevelOff)
 	checkResult(t, LogLevelOff)
 
-	_ = os.Remove(defaultLogger.Path + defaultLogger.FileName)
+	_ = os.Remove(getDefaultLogger().Path + getDefaultLogger().FileName)
+}
+
+func TestSync(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_sync", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	if err = l.SetSyncPolicy(SyncPolicyInterval, 0); err != ErrInvalidSyncInterval {
+		t.Error("Zero sync interval should be rejected", err)
+	}
+	for _, policy := range []uint8{SyncPolicyAlways, SyncPolicyError,
+		SyncPolicyInterval, SyncPolicyNever} {
+		if err = l.SetSyncPolicy(policy, time.Millisecond); err != nil {
+			t.Fatal("Set sync policy failed.", err)
+		}
+		if l.GetSyncPolicy() != policy {
+			t.Error("Sync policy is", l.GetSyncPolicy(), "not", policy)
+		}
+		l.Info("sync policy", policy)
+		l.Error("sync policy", policy)
+	}
+	if err = l.Sync(); err != nil {
+		t.Error("Sync failed.", err)
+	}
+}
+
+func TestSyncPolicyClose(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_sync_close", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(l.Path + l.FileName) }()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.SetSyncPolicy(SyncPolicyInterval, time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	l.Close()
+	if err = l.SetSyncPolicy(SyncPolicyInterval, time.Millisecond); err != ErrLoggerClosed {
+		t.Error("Set sync policy after close should fail", err)
+	}
+	l.syncMutex.Lock()
+	defer l.syncMutex.Unlock()
+	if l.syncStop != nil {
+		t.Error("Sync coroutine is left after close")
+	}
+}
+
+func TestFields(t *testing.T) {
+	l, err := NewInternal("./", "zlogger_fields", false, LogLevelAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		l.Close()
+		_ = os.Remove(l.Path + l.FileName)
+	}()
+	fields := []Field{String("user", "tom"), String("query", "a = b"), Int("id", -3),
+		Uint64("size", 9), Float64("rate", 0.5), Bool("ok", true),
+		Duration("cost", 1500*time.Millisecond)}
+	l.InfoW("typed fields", fields...)
+	l.SetEncoder(JSONEncoder{})
+	l.InfoW("typed fields", fields...)
+
+	f, err := os.Open(l.Path + l.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	reader := bufio.NewReader(f)
+	line := readLine(reader)
+	if !strings.HasSuffix(line, `[INFO] typed fields user=tom query="a = b" id=-3 size=9 rate=0.5 ok=true cost=1.5s`) {
+		t.Error("Text fields log line is", line)
+	}
+	line = readLine(reader)
+	if !strings.HasSuffix(line, `"msg":"typed fields","user":"tom","query":"a = b","id":-3,"size":9,"rate":0.5,"ok":true,"cost":"1.5s"}`) {
+		t.Error("JSON fields log line is", line)
+	}
+}
+
+func TestDisabledAllocs(t *testing.T) {
+	l, err := NewInternal(t.TempDir(), "zlogger_allocs", false, LogLevelError)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	id := 1000
+	allocs := testing.AllocsPerRun(100, func() {
+		id++
+		l.InfoW("disabled message", String("user", "tom"), Int("id", id))
+		l.InfoFn(func() string { return fmt.Sprint("disabled message ", id) })
+	})
+	if allocs != 0 {
+		t.Error("Disabled typed call allocates", allocs)
+	}
+}
+
+func newBenchLogger(b *testing.B, logLevel uint8) *Logger {
+	l, err := NewInternal(b.TempDir(), "zlogger_bench", false, logLevel)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(l.Close)
+	b.ReportAllocs()
+	b.ResetTimer()
+	return l
+}
+
+func BenchmarkDisabled(b *testing.B) {
+	l := newBenchLogger(b, LogLevelError)
+	for i := 0; i < b.N; i++ {
+		l.Info("disabled message")
+	}
+}
+
+// BenchmarkDisabledArgs box non-constant arguments before level is checked,
+// it allocates like BenchmarkDisabledF.
+func BenchmarkDisabledArgs(b *testing.B) {
+	l := newBenchLogger(b, LogLevelError)
+	for i := 0; i < b.N; i++ {
+		l.Info("disabled message", "user", i)
+	}
+}
+
+func BenchmarkDisabledF(b *testing.B) {
+	l := newBenchLogger(b, LogLevelError)
+	for i := 0; i < b.N; i++ {
+		l.InfoF("disabled message user %d", i)
+	}
+}
+
+func BenchmarkDisabledFn(b *testing.B) {
+	l := newBenchLogger(b, LogLevelError)
+	for i := 0; i < b.N; i++ {
+		l.InfoFn(func() string { return fmt.Sprint("disabled message user ", i) })
+	}
+}
+
+func BenchmarkDisabledFields(b *testing.B) {
+	l := newBenchLogger(b, LogLevelError)
+	for i := 0; i < b.N; i++ {
+		l.InfoW("disabled message", String("user", "tom"), Int("id", i),
+			Duration("cost", time.Second))
+	}
+}
+
+func BenchmarkEnabled(b *testing.B) {
+	l := newBenchLogger(b, LogLevelAll)
+	for i := 0; i < b.N; i++ {
+		l.Info("enabled message")
+	}
+}
+
+func BenchmarkEnabledArgs(b *testing.B) {
+	l := newBenchLogger(b, LogLevelAll)
+	for i := 0; i < b.N; i++ {
+		l.Info("enabled message", "user", i)
+	}
+}
+
+func BenchmarkEnabledF(b *testing.B) {
+	l := newBenchLogger(b, LogLevelAll)
+	for i := 0; i < b.N; i++ {
+		l.InfoF("enabled message user %s id %d", "tom", i)
+	}
+}
+
+func BenchmarkEnabledFields(b *testing.B) {
+	l := newBenchLogger(b, LogLevelAll)
+	for i := 0; i < b.N; i++ {
+		l.InfoW("enabled message", String("user", "tom"), Int("id", i),
+			Duration("cost", time.Second))
+	}
+}
+
+func BenchmarkEnabledJSON(b *testing.B) {
+	l := newBenchLogger(b, LogLevelAll)
+	l.SetEncoder(JSONEncoder{})
+	for i := 0; i < b.N; i++ {
+		l.InfoW("enabled message", String("user", "tom"), Int("id", i))
+	}
 }
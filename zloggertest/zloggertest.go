@@ -0,0 +1,126 @@
+// Package zloggertest provide a zlogger.Logger for tests,
+// entries are recorded in memory & printed by testing.TB.Log.
+package zloggertest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zhangyu0310/zlogger"
+)
+
+// Logs record entries of logger created by New.
+// It is a zlogger.Sink, entries are kept as clones.
+type Logs struct {
+	Logger *zlogger.Logger
+
+	tb      testing.TB
+	mutex   sync.Mutex
+	entries []*zlogger.Entry
+	done    bool // Test is finished, don't call tb.Log
+}
+
+// New create a logger at LogLevelAll, which record entries to Logs,
+// and print them by tb.Log. Log file is in tb.TempDir,
+// logger is closed when test is finished.
+func New(tb testing.TB) *Logs {
+	tb.Helper()
+	l, err := zlogger.NewInternal(tb.TempDir(), "zloggertest", false, zlogger.LogLevelAll)
+	if err != nil {
+		tb.Fatal("Create logger failed.", err)
+	}
+	logs := &Logs{Logger: l, tb: tb}
+	l.AddSink(logs)
+	tb.Cleanup(func() {
+		logs.mutex.Lock()
+		logs.done = true
+		logs.mutex.Unlock()
+		l.Close()
+	})
+	return logs
+}
+
+// NewDefault create a logger like New & set it as default logger,
+// old default logger is restored when test is finished.
+func NewDefault(tb testing.TB) *Logs {
+	tb.Helper()
+	logs := New(tb)
+	restore := zlogger.SetDefault(logs.Logger)
+	tb.Cleanup(restore)
+	return logs
+}
+
+func (logs *Logs) Write(e *zlogger.Entry) error {
+	logs.mutex.Lock()
+	defer logs.mutex.Unlock()
+	logs.entries = append(logs.entries, e.Clone())
+	if !logs.done {
+		line := zlogger.TextEncoder{}.Encode(nil, e)
+		logs.tb.Log(strings.TrimSuffix(string(line), "\n"))
+	}
+	return nil
+}
+
+func (logs *Logs) Sync() error {
+	return nil
+}
+
+func (logs *Logs) Close() error {
+	return nil
+}
+
+// Entries get entries recorded, in order.
+func (logs *Logs) Entries() []*zlogger.Entry {
+	logs.mutex.Lock()
+	defer logs.mutex.Unlock()
+	return append([]*zlogger.Entry(nil), logs.entries...)
+}
+
+// Reset drop entries recorded.
+func (logs *Logs) Reset() {
+	logs.mutex.Lock()
+	defer logs.mutex.Unlock()
+	logs.entries = nil
+}
+
+// Find get entries at level, which message or errors contain substring.
+func (logs *Logs) Find(level uint8, substring string) []*zlogger.Entry {
+	var found []*zlogger.Entry
+	for _, e := range logs.Entries() {
+		if e.Level == level && contains(e, substring) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+func contains(e *zlogger.Entry, substring string) bool {
+	if strings.Contains(e.Message, substring) {
+		return true
+	}
+	for _, err := range e.Errors {
+		if strings.Contains(fmt.Sprint(err), substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireLogged fail tb now if there is no entry at level contains substring.
+func (logs *Logs) RequireLogged(tb testing.TB, level uint8, substring string) {
+	tb.Helper()
+	if len(logs.Find(level, substring)) == 0 {
+		tb.Fatalf("No %s entry contains %q", zlogger.LogLevel2Str(level), substring)
+	}
+}
+
+// RequireNotLogged fail tb now if there is an entry at level contains substring.
+func (logs *Logs) RequireNotLogged(tb testing.TB, level uint8, substring string) {
+	tb.Helper()
+	if found := logs.Find(level, substring); len(found) > 0 {
+		tb.Fatalf("%s entry contains %q: %s",
+			zlogger.LogLevel2Str(level), substring, found[0].Message)
+	}
+}
@@ -0,0 +1,65 @@
+package zloggertest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zhangyu0310/zlogger"
+)
+
+// fakeTB record failure instead of stopping the test.
+type fakeTB struct {
+	testing.TB
+	failure string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.failure = fmt.Sprintf(format, args...)
+}
+
+func TestLogs(t *testing.T) {
+	logs := New(t)
+	logs.Logger.Named("db").InfoW("connected", zlogger.String("host", "db-1"))
+	logs.Logger.Error("query failed:", errors.New("timeout"))
+
+	entries := logs.Entries()
+	if len(entries) != 2 || entries[0].Component != "db" ||
+		entries[0].Fields[0].String != "db-1" || entries[1].Errors[0].Error() != "timeout" {
+		t.Fatal("Entries are wrong", entries)
+	}
+	logs.RequireLogged(t, zlogger.LogLevelInfo, "connect")
+	logs.RequireLogged(t, zlogger.LogLevelError, "timeout")
+	logs.RequireNotLogged(t, zlogger.LogLevelWarn, "connect")
+
+	fake := &fakeTB{TB: t}
+	logs.RequireLogged(fake, zlogger.LogLevelWarn, "connect")
+	if fake.failure != `No warn entry contains "connect"` {
+		t.Error("Failure is", fake.failure)
+	}
+	fake.failure = ""
+	logs.RequireNotLogged(fake, zlogger.LogLevelError, "query")
+	if fake.failure == "" {
+		t.Error("RequireNotLogged should fail")
+	}
+
+	logs.Reset()
+	if len(logs.Entries()) != 0 {
+		t.Error("Entries are not reset")
+	}
+}
+
+func TestNewDefault(t *testing.T) {
+	t.Run("swap", func(t *testing.T) {
+		logs := NewDefault(t)
+		zlogger.Warn("from package level")
+		logs.RequireLogged(t, zlogger.LogLevelWarn, "from package level")
+	})
+	logs := NewDefault(t)
+	zlogger.Info("after restore")
+	if len(logs.Entries()) != 1 {
+		t.Error("Entries are", logs.Entries())
+	}
+}